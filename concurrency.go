@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// handleTable 是一个线程安全的不透明句柄表，用来把chan、sync.WaitGroup、
+// sync.Mutex这类无法直接塞进JSON值里的Go运行时对象暴露给JSONLang程序。
+// 调用方只拿到一个字符串句柄，真正的对象留在后端内部。
+type handleTable struct {
+	mu    sync.Mutex
+	items map[string]interface{}
+	next  uint64
+}
+
+func newHandleTable() *handleTable {
+	return &handleTable{
+		items: make(map[string]interface{}),
+	}
+}
+
+func (ht *handleTable) store(value interface{}) string {
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+	ht.next++
+	handle := fmt.Sprintf("handle-%d", ht.next)
+	ht.items[handle] = value
+	return handle
+}
+
+func (ht *handleTable) load(handle string) (interface{}, bool) {
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+	value, ok := ht.items[handle]
+	return value, ok
+}
+
+// goSpawn 在新的goroutine中执行一个JSONLang函数，函数拥有自己独立的作用域；
+// 参数切片是复制出来的，但数组、map这类参数值本身仍与调用者共享。
+func (gb *GoBackend) goSpawn(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: go_spawn需要函数名参数")
+	}
+	funcName := toString(args[0])
+	if gb.program == nil {
+		return fmt.Errorf("错误: 当前没有正在执行的程序，无法解析函数 '%s'", funcName)
+	}
+	if !gb.program.HasFunction(funcName) {
+		return fmt.Errorf("错误: 函数 '%s' 未定义", funcName)
+	}
+	spawnArgs := append([]interface{}{}, args[1:]...)
+	program := gb.program
+	go executeFunction(program, gb, funcName, spawnArgs)
+	return nil
+}
+
+func (gb *GoBackend) chanMake(args ...interface{}) interface{} {
+	bufsize := 0
+	if len(args) > 0 {
+		bufsize = int(toNumber(args[0]))
+	}
+	ch := make(chan interface{}, bufsize)
+	return gb.handles.store(ch)
+}
+
+func (gb *GoBackend) chanSend(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: chan_send需要句柄和值两个参数")
+	}
+	ch, err := gb.loadChan(args[0])
+	if err != nil {
+		return err
+	}
+	ch <- args[1]
+	return nil
+}
+
+func (gb *GoBackend) chanRecv(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: chan_recv需要一个句柄参数")
+	}
+	ch, err := gb.loadChan(args[0])
+	if err != nil {
+		return err
+	}
+	value, ok := <-ch
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+func (gb *GoBackend) chanClose(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: chan_close需要一个句柄参数")
+	}
+	ch, err := gb.loadChan(args[0])
+	if err != nil {
+		return err
+	}
+	close(ch)
+	return nil
+}
+
+func (gb *GoBackend) loadChan(handle interface{}) (chan interface{}, error) {
+	value, ok := gb.handles.load(toString(handle))
+	if !ok {
+		return nil, fmt.Errorf("错误: 无效的channel句柄 '%v'", handle)
+	}
+	ch, ok := value.(chan interface{})
+	if !ok {
+		return nil, fmt.Errorf("错误: 句柄 '%v' 不是一个channel", handle)
+	}
+	return ch, nil
+}
+
+func (gb *GoBackend) wgNew(args ...interface{}) interface{} {
+	return gb.handles.store(&sync.WaitGroup{})
+}
+
+func (gb *GoBackend) wgAdd(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: wg_add需要句柄和增量两个参数")
+	}
+	wg, err := gb.loadWaitGroup(args[0])
+	if err != nil {
+		return err
+	}
+	wg.Add(int(toNumber(args[1])))
+	return nil
+}
+
+func (gb *GoBackend) wgDone(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: wg_done需要一个句柄参数")
+	}
+	wg, err := gb.loadWaitGroup(args[0])
+	if err != nil {
+		return err
+	}
+	wg.Done()
+	return nil
+}
+
+func (gb *GoBackend) wgWait(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: wg_wait需要一个句柄参数")
+	}
+	wg, err := gb.loadWaitGroup(args[0])
+	if err != nil {
+		return err
+	}
+	wg.Wait()
+	return nil
+}
+
+func (gb *GoBackend) loadWaitGroup(handle interface{}) (*sync.WaitGroup, error) {
+	value, ok := gb.handles.load(toString(handle))
+	if !ok {
+		return nil, fmt.Errorf("错误: 无效的WaitGroup句柄 '%v'", handle)
+	}
+	wg, ok := value.(*sync.WaitGroup)
+	if !ok {
+		return nil, fmt.Errorf("错误: 句柄 '%v' 不是一个WaitGroup", handle)
+	}
+	return wg, nil
+}
+
+func (gb *GoBackend) mutexNew(args ...interface{}) interface{} {
+	return gb.handles.store(&sync.Mutex{})
+}
+
+func (gb *GoBackend) mutexLock(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: mutex_lock需要一个句柄参数")
+	}
+	mu, err := gb.loadMutex(args[0])
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	return nil
+}
+
+func (gb *GoBackend) mutexUnlock(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: mutex_unlock需要一个句柄参数")
+	}
+	mu, err := gb.loadMutex(args[0])
+	if err != nil {
+		return err
+	}
+	mu.Unlock()
+	return nil
+}
+
+func (gb *GoBackend) loadMutex(handle interface{}) (*sync.Mutex, error) {
+	value, ok := gb.handles.load(toString(handle))
+	if !ok {
+		return nil, fmt.Errorf("错误: 无效的Mutex句柄 '%v'", handle)
+	}
+	mu, ok := value.(*sync.Mutex)
+	if !ok {
+		return nil, fmt.Errorf("错误: 句柄 '%v' 不是一个Mutex", handle)
+	}
+	return mu, nil
+}
+
+// atomicCounter 按名称懒加载一个*int64计数器，同名的counter在整个后端生命周期内共享。
+func (gb *GoBackend) atomicCounter(name string) *int64 {
+	gb.atomMu.Lock()
+	defer gb.atomMu.Unlock()
+	counter, exists := gb.atomics[name]
+	if !exists {
+		counter = new(int64)
+		gb.atomics[name] = counter
+	}
+	return counter
+}
+
+func (gb *GoBackend) atomicAdd(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: atomic_add需要计数器名和增量两个参数")
+	}
+	counter := gb.atomicCounter(toString(args[0]))
+	delta := int64(toNumber(args[1]))
+	return float64(atomic.AddInt64(counter, delta))
+}
+
+func (gb *GoBackend) atomicLoad(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: atomic_load需要一个计数器名参数")
+	}
+	counter := gb.atomicCounter(toString(args[0]))
+	return float64(atomic.LoadInt64(counter))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+func (gb *GoBackend) md5Hash(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: md5需要一个字符串参数")
+	}
+	sum := md5.Sum([]byte(toString(args[0])))
+	return hex.EncodeToString(sum[:])
+}
+
+func (gb *GoBackend) sha1Hash(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: sha1需要一个字符串参数")
+	}
+	sum := sha1.Sum([]byte(toString(args[0])))
+	return hex.EncodeToString(sum[:])
+}
+
+func (gb *GoBackend) sha256Hash(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: sha256需要一个字符串参数")
+	}
+	sum := sha256.Sum256([]byte(toString(args[0])))
+	return hex.EncodeToString(sum[:])
+}
+
+func (gb *GoBackend) hexEncode(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: hex_encode需要一个字符串参数")
+	}
+	return hex.EncodeToString([]byte(toString(args[0])))
+}
+
+func (gb *GoBackend) hexDecode(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: hex_decode需要一个字符串参数")
+	}
+	decoded, err := hex.DecodeString(toString(args[0]))
+	if err != nil {
+		return fmt.Errorf("错误: 无效的hex字符串: %v", err)
+	}
+	return string(decoded)
+}
+
+func (gb *GoBackend) base64Encode(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: base64_encode需要一个字符串参数")
+	}
+	return base64.StdEncoding.EncodeToString([]byte(toString(args[0])))
+}
+
+func (gb *GoBackend) base64Decode(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: base64_decode需要一个字符串参数")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(toString(args[0]))
+	if err != nil {
+		return fmt.Errorf("错误: 无效的base64字符串: %v", err)
+	}
+	return string(decoded)
+}
+
+func (gb *GoBackend) base64URLEncode(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: base64_url_encode需要一个字符串参数")
+	}
+	return base64.URLEncoding.EncodeToString([]byte(toString(args[0])))
+}
+
+func (gb *GoBackend) base64URLDecode(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: base64_url_decode需要一个字符串参数")
+	}
+	decoded, err := base64.URLEncoding.DecodeString(toString(args[0]))
+	if err != nil {
+		return fmt.Errorf("错误: 无效的base64url字符串: %v", err)
+	}
+	return string(decoded)
+}
+
+// uuidV4 生成一个随机的UUID v4：16字节随机数，按规范设置版本和变体半字节，
+// 再格式化成标准的8-4-4-4-12分组。
+func (gb *GoBackend) uuidV4(args ...interface{}) interface{} {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("错误: 无法生成随机数: %v", err)
+	}
+	buf[6] = (buf[6] & 0x0f) | 0x40 // version 4
+	buf[8] = (buf[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16])
+}
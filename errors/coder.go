@@ -0,0 +1,132 @@
+// Package errors提供一个线程安全的结构化错误码注册表：每个错误码关联
+// 一个HTTP状态码和一个供排障参考的文档链接，使JSON程序可以按错误码
+// 而不是按Chinese-only的错误文案来捕获和分类错误。
+package errors
+
+import (
+	"fmt"
+	"sync"
+)
+
+// UnknownErrorCode是注册表中保留给"无法识别"错误的兜底码。
+const UnknownErrorCode = 999999
+
+// Coder是一个可被注册、可在JSONLang程序里通过错误码识别的结构化错误类型。
+type Coder interface {
+	Code() int
+	HTTPStatus() int
+	String() string
+	Reference() string
+}
+
+var (
+	mu       sync.Mutex
+	registry = make(map[int]Coder)
+)
+
+// Register把一个Coder加入全局注册表，若该Code()已被占用则返回错误。
+func Register(c Coder) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := registry[c.Code()]; exists {
+		return fmt.Errorf("错误码 %d 已被注册", c.Code())
+	}
+	registry[c.Code()] = c
+	return nil
+}
+
+// MustRegister和Register相同，但注册失败时直接panic，用于包级别的init()。
+func MustRegister(c Coder) {
+	if err := Register(c); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup按错误码查找已注册的Coder，找不到时返回UnknownErrorCode对应的兜底值。
+func Lookup(code int) Coder {
+	mu.Lock()
+	defer mu.Unlock()
+	if c, ok := registry[code]; ok {
+		return c
+	}
+	return registry[UnknownErrorCode]
+}
+
+// CodedError把一个Coder和具体的出错上下文message绑在一起并实现error接口，
+// 可以在JSONLang程序里通过error_code/error_http_status/error_reference检查。
+type CodedError struct {
+	Coder   Coder
+	Message string
+}
+
+func New(c Coder, message string) *CodedError {
+	return &CodedError{Coder: c, Message: message}
+}
+
+func (e *CodedError) Error() string {
+	return fmt.Sprintf("[%d] %s: %s", e.Coder.Code(), e.Coder.String(), e.Message)
+}
+
+type unknownCoder struct{}
+
+func (unknownCoder) Code() int         { return UnknownErrorCode }
+func (unknownCoder) HTTPStatus() int   { return 500 }
+func (unknownCoder) String() string    { return "未知错误" }
+func (unknownCoder) Reference() string { return "" }
+
+// basicCoder是Coder的一个通用实现，满足大多数"固定文案+固定状态码"的场景，
+// 不需要为每一个错误码单独定义具名类型。
+type basicCoder struct {
+	code       int
+	httpStatus int
+	str        string
+	reference  string
+}
+
+func (b basicCoder) Code() int         { return b.code }
+func (b basicCoder) HTTPStatus() int   { return b.httpStatus }
+func (b basicCoder) String() string    { return b.str }
+func (b basicCoder) Reference() string { return b.reference }
+
+// 解释器本身在函数调用分发过程中会遇到的错误码，均在这里集中注册。
+var (
+	ErrMissingFunctionField = basicCoder{
+		code:       100001,
+		httpStatus: 400,
+		str:        "函数调用缺少function字段",
+		reference:  "https://github.com/Llonvne/jsonlang/wiki/errors#100001",
+	}
+	ErrModuleLoadFailed = basicCoder{
+		code:       100002,
+		httpStatus: 502,
+		str:        "加载第三方模块失败",
+		reference:  "https://github.com/Llonvne/jsonlang/wiki/errors#100002",
+	}
+	ErrFunctionNotInModule = basicCoder{
+		code:       100003,
+		httpStatus: 404,
+		str:        "模块中没有对应的函数",
+		reference:  "https://github.com/Llonvne/jsonlang/wiki/errors#100003",
+	}
+	ErrTypeCoercionFailed = basicCoder{
+		code:       100004,
+		httpStatus: 400,
+		str:        "参数类型强制转换失败",
+		reference:  "https://github.com/Llonvne/jsonlang/wiki/errors#100004",
+	}
+	ErrUnknownType = basicCoder{
+		code:       100005,
+		httpStatus: 400,
+		str:        "未注册的类型",
+		reference:  "https://github.com/Llonvne/jsonlang/wiki/errors#100005",
+	}
+)
+
+func init() {
+	MustRegister(unknownCoder{})
+	MustRegister(ErrMissingFunctionField)
+	MustRegister(ErrModuleLoadFailed)
+	MustRegister(ErrFunctionNotInModule)
+	MustRegister(ErrTypeCoercionFailed)
+	MustRegister(ErrUnknownType)
+}
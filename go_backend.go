@@ -8,9 +8,13 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	jsonlangerrors "github.com/Llonvne/jsonlang/errors"
 )
 
 // JSON程序结构
@@ -20,6 +24,8 @@ type JSONProgram struct {
 	Functions     map[string]map[string]interface{} `json:"functions"`
 	Modifiers     []map[string]interface{}          `json:"modifiers"`
 	LoadedModules map[string]*JSONProgram           `json:"-"` // 存储已加载的模块
+	Resolver      ModuleResolver                    `json:"-"` // 第三方模块的解析策略，为nil时取defaultModuleResolver()
+	moduleCache   *moduleLRUCache                   // 按模块路径+内容哈希寻址的解析结果缓存
 }
 
 // 创建新的JSON程序
@@ -92,60 +98,95 @@ func (jp *JSONProgram) GetFunction(name string) (map[string]interface{}, bool) {
 	return funcData, exists
 }
 
-// 加载模块
+// 加载模块：实际解析逻辑在loadModuleEntry（module_resolver.go）中，按
+// jp.Resolver（未设置时为defaultModuleResolver）查找模块来源；同一程序内
+// 已加载过的模块直接从LoadedModules返回，不再拉取，moduleCache则让内容
+// 哈希相同的源码不再重复解析。
 func (jp *JSONProgram) LoadModule(modulePath string) (*JSONProgram, error) {
-	// 检查是否已加载
-	if module, exists := jp.LoadedModules[modulePath]; exists {
-		return module, nil
-	}
-
-	// 尝试不同的文件扩展名和路径
-	possiblePaths := []string{
-		modulePath + ".json",
-		modulePath + "",
-		// 尝试从包路径中提取文件名
-		strings.Split(modulePath, ".")[len(strings.Split(modulePath, "."))-1] + ".json",
-		strings.Split(modulePath, ".")[len(strings.Split(modulePath, "."))-1] + "",
+	module, _, err := jp.loadModuleEntry(modulePath)
+	return module, err
+}
+
+// Backend 是语言后端的统一接口，每种后端（Go、Python子进程、WASM、远程RPC等）
+// 都通过实现这个接口接入解释器，解释器本身不再关心具体后端是什么。
+type Backend interface {
+	GetName() string
+	GetVersion() string
+	GetFunctions() map[string]func(args ...interface{}) interface{}
+	ExecuteFunction(funcName string, args ...interface{}) interface{}
+	RegisterFunction(name string, fn func(args ...interface{}) interface{})
+	Capabilities() map[string]bool
+	// SetProgram 让后端持有当前正在执行的JSONProgram，以便像go_spawn、array_sort
+	// 这类需要回调JSONLang函数的内置函数能找到函数定义并重新进入解释器。
+	SetProgram(program *JSONProgram)
+	// GetFunctionByImplName 将stdlib.go.json中声明的实现名解析为该后端自己的函数；
+	// 导出是因为Backend接口本身就是为了让其他package实现的后端（Python子进程、
+	// WASM、远程RPC等）也能接入解释器，未导出的方法做不到这一点。
+	GetFunctionByImplName(implName string) func(args ...interface{}) interface{}
+	// CoerceType 把一个类型名（比如"Int"、"List<String>"）和原始JSON解码值强制转换成
+	// 对应的Go类型值，供evaluateExpression处理String/Number/Boolean之外的表达式类型。
+	CoerceType(typeName string, raw interface{}) (interface{}, error)
+}
+
+// BackendRegistry 按名称管理后端工厂，使JSON程序可以通过metadata.backend字段
+// 在运行时选择目标后端，而不需要修改解释器本身。
+type BackendRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]func() Backend
+}
+
+func NewBackendRegistry() *BackendRegistry {
+	return &BackendRegistry{
+		factories: make(map[string]func() Backend),
+	}
+}
+
+// Register 注册一个后端工厂，名称重复时覆盖旧的注册。
+func (br *BackendRegistry) Register(name string, factory func() Backend) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	br.factories[name] = factory
+}
+
+// Create 按名称创建一个后端实例。
+func (br *BackendRegistry) Create(name string) (Backend, bool) {
+	br.mu.RLock()
+	factory, exists := br.factories[name]
+	br.mu.RUnlock()
+	if !exists {
+		return nil, false
 	}
+	return factory(), true
+}
 
-	var moduleFile string
-
-	for _, testPath := range possiblePaths {
-		if _, err := os.Stat(testPath); err == nil {
-			moduleFile = testPath
-			break
-		}
-	}
+// defaultBackendRegistry 是进程内全局的后端注册表，内置的Go后端在init()中注册。
+var defaultBackendRegistry = NewBackendRegistry()
 
-	if moduleFile == "" {
-		return nil, fmt.Errorf("找不到模块文件: %s", modulePath)
-	}
+func init() {
+	defaultBackendRegistry.Register("go", func() Backend {
+		return NewGoBackend()
+	})
+}
 
-	// 读取模块文件
-	data, err := ioutil.ReadFile(moduleFile)
-	if err != nil {
-		return nil, fmt.Errorf("无法读取模块文件 '%s': %v", modulePath, err)
-	}
+// Go后端实现
+type GoBackend struct {
+	name         string
+	version      string
+	functions    map[string]func(args ...interface{}) interface{}
+	stdlibData   map[string]interface{}
+	stdlibLoaded bool         // 是否成功从stdlib.go.json加载到了函数定义，而不是仅仅初始化了一个空map
+	program      *JSONProgram // 当前正在执行的程序，供go_spawn、array_sort等回调使用
 
-	// 解析JSON
-	var moduleData map[string]interface{}
-	if err := json.Unmarshal(data, &moduleData); err != nil {
-		return nil, fmt.Errorf("模块文件JSON格式错误: %v", err)
-	}
+	handles *handleTable      // chan/WaitGroup/Mutex等句柄的存放处
+	atomics map[string]*int64 // atomic_add/atomic_load按名称定位的计数器
+	atomMu  sync.Mutex        // 保护atomics的懒初始化
 
-	// 创建模块程序
-	moduleProgram := NewJSONProgram(moduleData)
-	jp.LoadedModules[modulePath] = moduleProgram
+	httpRoutes   map[string]string // http_route注册的路径到JSONLang函数名的映射
+	httpRoutesMu sync.Mutex        // 保护httpRoutes
 
-	return moduleProgram, nil
-}
+	regexCache *regexCache // regex_*系列函数的已编译正则表达式缓存
 
-// Go后端实现
-type GoBackend struct {
-	name       string
-	version    string
-	functions  map[string]func(args ...interface{}) interface{}
-	stdlibData map[string]interface{}
+	types *TypeRegistry // 除String/Number/Boolean外的类型强制转换表，供evaluateExpression和Register使用
 }
 
 func NewGoBackend() *GoBackend {
@@ -154,12 +195,22 @@ func NewGoBackend() *GoBackend {
 		version:    "1.0.0",
 		functions:  make(map[string]func(args ...interface{}) interface{}),
 		stdlibData: make(map[string]interface{}),
+		handles:    newHandleTable(),
+		atomics:    make(map[string]*int64),
+		httpRoutes: make(map[string]string),
+		regexCache: newRegexCache(),
+		types:      newTypeRegistry(),
 	}
 	backend.loadStdlib()
 	backend.registerFunctions()
 	return backend
 }
 
+// SetProgram 记录当前执行的JSONProgram，供go_spawn等需要重新进入解释器的内置函数使用。
+func (gb *GoBackend) SetProgram(program *JSONProgram) {
+	gb.program = program
+}
+
 func (gb *GoBackend) GetName() string {
 	return gb.name
 }
@@ -172,6 +223,20 @@ func (gb *GoBackend) GetFunctions() map[string]func(args ...interface{}) interfa
 	return gb.functions
 }
 
+// RegisterFunction 允许调用方（或其他后端）动态追加一个函数实现。
+func (gb *GoBackend) RegisterFunction(name string, fn func(args ...interface{}) interface{}) {
+	gb.functions[name] = fn
+}
+
+// Capabilities 描述该后端支持的可选能力，供解释器或工具在运行前做特性检测。
+func (gb *GoBackend) Capabilities() map[string]bool {
+	return map[string]bool{
+		"concurrency": true,
+		"filesystem":  true,
+		"network":     true,
+	}
+}
+
 func (gb *GoBackend) loadStdlib() {
 	// 读取Go标准库定义
 	data, err := ioutil.ReadFile("stdlib.go.json")
@@ -187,6 +252,8 @@ func (gb *GoBackend) loadStdlib() {
 		gb.stdlibData = make(map[string]interface{})
 		return
 	}
+
+	gb.stdlibLoaded = true
 }
 
 func (gb *GoBackend) ExecuteFunction(funcName string, args ...interface{}) interface{} {
@@ -197,14 +264,15 @@ func (gb *GoBackend) ExecuteFunction(funcName string, args ...interface{}) inter
 }
 
 func (gb *GoBackend) registerFunctions() {
-	// 如果加载了stdlib文件，根据stdlib定义注册函数
-	if gb.stdlibData != nil {
+	// 只有真正从stdlib.go.json加载到数据时才走stdlib驱动的注册路径；
+	// stdlibData在加载失败时仍然是一个非nil的空map，不能用nil判断来区分两种情况。
+	if gb.stdlibLoaded {
 		if functions, ok := gb.stdlibData["functions"].(map[string]interface{}); ok {
 			for funcName, funcInfo := range functions {
 				if funcInfoMap, ok := funcInfo.(map[string]interface{}); ok {
 					if implName, ok := funcInfoMap["implementation"].(string); ok {
 						// 根据实现名称映射到实际函数
-						if function := gb.getFunctionByImplName(implName); function != nil {
+						if function := gb.GetFunctionByImplName(implName); function != nil {
 							gb.functions[funcName] = function
 						} else {
 							fmt.Printf("警告: 找不到实现函数 '%s' 用于 '%s'\n", implName, funcName)
@@ -259,6 +327,9 @@ func (gb *GoBackend) registerDefaultFunctions() {
 	gb.functions["array_length"] = gb.arrayLength
 	gb.functions["array_sort"] = gb.arraySort
 	gb.functions["array_reverse"] = gb.arrayReverse
+	gb.functions["array_filter"] = gb.arrayFilter
+	gb.functions["array_map"] = gb.arrayMap
+	gb.functions["array_reduce"] = gb.arrayReduce
 
 	// 系统函数
 	gb.functions["sleep"] = gb.sleep
@@ -267,6 +338,58 @@ func (gb *GoBackend) registerDefaultFunctions() {
 	gb.functions["time_now"] = gb.timeNow
 	gb.functions["exit"] = gb.exit
 
+	// 并发函数
+	gb.functions["go_spawn"] = gb.goSpawn
+	gb.functions["chan_make"] = gb.chanMake
+	gb.functions["chan_send"] = gb.chanSend
+	gb.functions["chan_recv"] = gb.chanRecv
+	gb.functions["chan_close"] = gb.chanClose
+	gb.functions["wg_new"] = gb.wgNew
+	gb.functions["wg_add"] = gb.wgAdd
+	gb.functions["wg_done"] = gb.wgDone
+	gb.functions["wg_wait"] = gb.wgWait
+	gb.functions["atomic_add"] = gb.atomicAdd
+	gb.functions["atomic_load"] = gb.atomicLoad
+	gb.functions["mutex_new"] = gb.mutexNew
+	gb.functions["mutex_lock"] = gb.mutexLock
+	gb.functions["mutex_unlock"] = gb.mutexUnlock
+
+	// HTTP函数
+	gb.functions["http_get"] = gb.httpGet
+	gb.functions["http_post"] = gb.httpPost
+	gb.functions["http_request"] = gb.httpRequest
+	gb.functions["http_server_start"] = gb.httpServerStart
+	gb.functions["http_route"] = gb.httpRoute
+	gb.functions["http_get_host"] = gb.httpGetHost
+	gb.functions["http_get_domain"] = gb.httpGetDomain
+
+	// 加密/编码函数
+	gb.functions["md5"] = gb.md5Hash
+	gb.functions["sha1"] = gb.sha1Hash
+	gb.functions["sha256"] = gb.sha256Hash
+	gb.functions["hex_encode"] = gb.hexEncode
+	gb.functions["hex_decode"] = gb.hexDecode
+	gb.functions["base64_encode"] = gb.base64Encode
+	gb.functions["base64_decode"] = gb.base64Decode
+	gb.functions["base64_url_encode"] = gb.base64URLEncode
+	gb.functions["base64_url_decode"] = gb.base64URLDecode
+	gb.functions["uuid_v4"] = gb.uuidV4
+
+	// 正则表达式函数
+	gb.functions["regex_match"] = gb.regexMatch
+	gb.functions["regex_find"] = gb.regexFind
+	gb.functions["regex_find_all"] = gb.regexFindAll
+	gb.functions["regex_replace"] = gb.regexReplace
+	gb.functions["regex_split"] = gb.regexSplit
+	gb.functions["regex_compile"] = gb.regexCompile
+
+	// 结构化错误函数
+	gb.functions["is_error"] = gb.isError
+	gb.functions["error_code"] = gb.errorCode
+	gb.functions["error_message"] = gb.errorMessage
+	gb.functions["error_http_status"] = gb.errorHTTPStatus
+	gb.functions["error_reference"] = gb.errorReference
+
 	// 类型转换函数
 	gb.functions["to_string"] = gb.toString
 	gb.functions["to_number"] = gb.toNumber
@@ -280,7 +403,7 @@ func (gb *GoBackend) registerDefaultFunctions() {
 	gb.functions["is_boolean"] = gb.isBoolean
 }
 
-func (gb *GoBackend) getFunctionByImplName(implName string) func(args ...interface{}) interface{} {
+func (gb *GoBackend) GetFunctionByImplName(implName string) func(args ...interface{}) interface{} {
 	// 根据实现名称映射到实际函数
 	switch implName {
 	// 标准库函数映射
@@ -346,6 +469,12 @@ func (gb *GoBackend) getFunctionByImplName(implName string) func(args ...interfa
 		return gb.arraySort
 	case "slice.Reverse", "array_reverse":
 		return gb.arrayReverse
+	case "array_filter":
+		return gb.arrayFilter
+	case "array_map":
+		return gb.arrayMap
+	case "array_reduce":
+		return gb.arrayReduce
 	case "time.Sleep", "sleep":
 		return gb.sleep
 	case "rand.Float64", "random":
@@ -356,6 +485,90 @@ func (gb *GoBackend) getFunctionByImplName(implName string) func(args ...interfa
 		return gb.timeNow
 	case "os.Exit", "exit":
 		return gb.exit
+	case "go.spawn", "go_spawn":
+		return gb.goSpawn
+	case "make.chan", "chan_make":
+		return gb.chanMake
+	case "chan.send", "chan_send":
+		return gb.chanSend
+	case "chan.recv", "chan_recv":
+		return gb.chanRecv
+	case "chan.close", "chan_close":
+		return gb.chanClose
+	case "sync.WaitGroup.new", "wg_new":
+		return gb.wgNew
+	case "sync.WaitGroup.Add", "wg_add":
+		return gb.wgAdd
+	case "sync.WaitGroup.Done", "wg_done":
+		return gb.wgDone
+	case "sync.WaitGroup.Wait", "wg_wait":
+		return gb.wgWait
+	case "sync/atomic.AddInt64", "atomic_add":
+		return gb.atomicAdd
+	case "sync/atomic.LoadInt64", "atomic_load":
+		return gb.atomicLoad
+	case "sync.Mutex.new", "mutex_new":
+		return gb.mutexNew
+	case "sync.Mutex.Lock", "mutex_lock":
+		return gb.mutexLock
+	case "sync.Mutex.Unlock", "mutex_unlock":
+		return gb.mutexUnlock
+	case "net/http.Get", "http_get":
+		return gb.httpGet
+	case "net/http.Post", "http_post":
+		return gb.httpPost
+	case "net/http.NewRequest", "http_request":
+		return gb.httpRequest
+	case "net/http.ListenAndServe", "http_server_start":
+		return gb.httpServerStart
+	case "http.ServeMux.HandleFunc", "http_route":
+		return gb.httpRoute
+	case "http_get_host":
+		return gb.httpGetHost
+	case "http_get_domain":
+		return gb.httpGetDomain
+	case "crypto/md5.Sum", "md5":
+		return gb.md5Hash
+	case "crypto/sha1.Sum", "sha1":
+		return gb.sha1Hash
+	case "crypto/sha256.Sum256", "sha256":
+		return gb.sha256Hash
+	case "encoding/hex.EncodeToString", "hex_encode":
+		return gb.hexEncode
+	case "encoding/hex.DecodeString", "hex_decode":
+		return gb.hexDecode
+	case "encoding/base64.StdEncoding.EncodeToString", "base64_encode":
+		return gb.base64Encode
+	case "encoding/base64.StdEncoding.DecodeString", "base64_decode":
+		return gb.base64Decode
+	case "encoding/base64.URLEncoding", "base64_url_encode":
+		return gb.base64URLEncode
+	case "encoding/base64.URLEncoding.DecodeString", "base64_url_decode":
+		return gb.base64URLDecode
+	case "uuid_v4":
+		return gb.uuidV4
+	case "regexp.MatchString", "regex_match":
+		return gb.regexMatch
+	case "regexp.FindString", "regex_find":
+		return gb.regexFind
+	case "regexp.FindAllString", "regex_find_all":
+		return gb.regexFindAll
+	case "regexp.ReplaceAllString", "regex_replace":
+		return gb.regexReplace
+	case "regexp.Split", "regex_split":
+		return gb.regexSplit
+	case "regexp.Compile", "regex_compile":
+		return gb.regexCompile
+	case "is_error":
+		return gb.isError
+	case "error_code":
+		return gb.errorCode
+	case "error_message":
+		return gb.errorMessage
+	case "error_http_status":
+		return gb.errorHTTPStatus
+	case "error_reference":
+		return gb.errorReference
 	case "fmt.Sprintf", "to_string":
 		return gb.toString
 	case "strconv.ParseFloat", "to_number":
@@ -433,7 +646,7 @@ func (gb *GoBackend) readFile(args ...interface{}) interface{} {
 	filename := toString(args[0])
 	content, err := ioutil.ReadFile(filename)
 	if err != nil {
-		return fmt.Sprintf("错误: 无法读取文件 '%s': %v", filename, err)
+		return NewJSONError("FILE_READ_FAILED", fmt.Sprintf("无法读取文件 '%s': %v", filename, err), map[string]interface{}{"filename": filename})
 	}
 	return string(content)
 }
@@ -446,7 +659,7 @@ func (gb *GoBackend) writeFile(args ...interface{}) interface{} {
 	content := toString(args[1])
 	err := ioutil.WriteFile(filename, []byte(content), 0644)
 	if err != nil {
-		return false
+		return NewJSONError("FILE_WRITE_FAILED", fmt.Sprintf("无法写入文件 '%s': %v", filename, err), map[string]interface{}{"filename": filename})
 	}
 	return true
 }
@@ -485,7 +698,7 @@ func (gb *GoBackend) divide(args ...interface{}) interface{} {
 	a := toNumber(args[0])
 	b := toNumber(args[1])
 	if b == 0 {
-		return fmt.Errorf("错误: 除数不能为零")
+		return NewJSONError("DIVISION_BY_ZERO", "除数不能为零", nil)
 	}
 	return a / b
 }
@@ -505,7 +718,7 @@ func (gb *GoBackend) sqrt(args ...interface{}) interface{} {
 	}
 	x := toNumber(args[0])
 	if x < 0 {
-		return fmt.Errorf("错误: 不能计算负数的平方根")
+		return NewJSONError("NEGATIVE_SQRT", "不能计算负数的平方根", map[string]interface{}{"value": x})
 	}
 	return math.Sqrt(x)
 }
@@ -623,7 +836,7 @@ func (gb *GoBackend) join(args ...interface{}) interface{} {
 	}
 	array, ok := args[0].([]interface{})
 	if !ok {
-		return fmt.Errorf("错误: 第一个参数必须是数组")
+		return NewJSONError("TYPE_MISMATCH", "第一个参数必须是数组", map[string]interface{}{"expected": "array"})
 	}
 	delimiter := toString(args[1])
 
@@ -655,10 +868,10 @@ func (gb *GoBackend) arrayPop(args ...interface{}) interface{} {
 	}
 	array, ok := args[0].([]interface{})
 	if !ok {
-		return fmt.Errorf("错误: 参数必须是数组")
+		return NewJSONError("TYPE_MISMATCH", "参数必须是数组", map[string]interface{}{"expected": "array"})
 	}
 	if len(array) == 0 {
-		return fmt.Errorf("错误: 数组为空")
+		return NewJSONError("ARRAY_EMPTY", "数组为空", nil)
 	}
 	last := array[len(array)-1]
 	return last
@@ -670,11 +883,11 @@ func (gb *GoBackend) arrayGet(args ...interface{}) interface{} {
 	}
 	array, ok := args[0].([]interface{})
 	if !ok {
-		return fmt.Errorf("错误: 第一个参数必须是数组")
+		return NewJSONError("TYPE_MISMATCH", "第一个参数必须是数组", map[string]interface{}{"expected": "array"})
 	}
 	index := int(toNumber(args[1]))
 	if index < 0 || index >= len(array) {
-		return fmt.Errorf("错误: 数组索引越界")
+		return NewJSONError("INDEX_OUT_OF_RANGE", "数组索引越界", map[string]interface{}{"index": index, "length": len(array)})
 	}
 	return array[index]
 }
@@ -685,11 +898,11 @@ func (gb *GoBackend) arraySet(args ...interface{}) interface{} {
 	}
 	array, ok := args[0].([]interface{})
 	if !ok {
-		return fmt.Errorf("错误: 第一个参数必须是数组")
+		return NewJSONError("TYPE_MISMATCH", "第一个参数必须是数组", map[string]interface{}{"expected": "array"})
 	}
 	index := int(toNumber(args[1]))
 	if index < 0 || index >= len(array) {
-		return fmt.Errorf("错误: 数组索引越界")
+		return NewJSONError("INDEX_OUT_OF_RANGE", "数组索引越界", map[string]interface{}{"index": index, "length": len(array)})
 	}
 	array[index] = args[2]
 	return array
@@ -706,6 +919,8 @@ func (gb *GoBackend) arrayLength(args ...interface{}) interface{} {
 	return len(array)
 }
 
+// arraySort对数组进行排序。第二个参数可以是一个JSONLang函数名，作为
+// less(a, b) bool比较器使用；省略时按数字/字典序做默认比较。
 func (gb *GoBackend) arraySort(args ...interface{}) interface{} {
 	if len(args) == 0 {
 		return []interface{}{}
@@ -715,12 +930,125 @@ func (gb *GoBackend) arraySort(args ...interface{}) interface{} {
 		return fmt.Errorf("错误: 第一个参数必须是数组")
 	}
 
-	// 简单的排序实现
 	sorted := make([]interface{}, len(array))
 	copy(sorted, array)
+
+	var less func(a, b interface{}) bool
+	if len(args) > 1 {
+		cmpName := toString(args[1])
+		if gb.program == nil {
+			return fmt.Errorf("错误: 当前没有正在执行的程序，无法解析比较函数 '%s'", cmpName)
+		}
+		if !gb.program.HasFunction(cmpName) {
+			return fmt.Errorf("错误: 比较函数 '%s' 未定义", cmpName)
+		}
+		program := gb.program
+		less = func(a, b interface{}) bool {
+			result := executeFunction(program, gb, cmpName, []interface{}{a, b})
+			return toBoolean(result)
+		}
+	} else {
+		less = defaultLess
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
 	return sorted
 }
 
+// defaultLess是array_sort在没有提供比较器时使用的默认顺序：数字按数值比较，
+// 其余类型一律按字符串表示的字典序比较。
+func defaultLess(a, b interface{}) bool {
+	af, aIsNum := a.(float64)
+	bf, bIsNum := b.(float64)
+	if aIsNum && bIsNum {
+		return af < bf
+	}
+	return toString(a) < toString(b)
+}
+
+// arrayFilter调用JSONLang函数predicate(item) bool，返回保留下来的元素组成的新数组。
+func (gb *GoBackend) arrayFilter(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: array_filter需要数组和函数名两个参数")
+	}
+	array, ok := args[0].([]interface{})
+	if !ok {
+		return fmt.Errorf("错误: 第一个参数必须是数组")
+	}
+	fn, err := gb.resolveCallback(args[1])
+	if err != nil {
+		return err
+	}
+
+	result := make([]interface{}, 0, len(array))
+	for _, item := range array {
+		if toBoolean(fn([]interface{}{item})) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// arrayMap调用JSONLang函数transform(item)，返回转换后元素组成的新数组。
+func (gb *GoBackend) arrayMap(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: array_map需要数组和函数名两个参数")
+	}
+	array, ok := args[0].([]interface{})
+	if !ok {
+		return fmt.Errorf("错误: 第一个参数必须是数组")
+	}
+	fn, err := gb.resolveCallback(args[1])
+	if err != nil {
+		return err
+	}
+
+	result := make([]interface{}, len(array))
+	for i, item := range array {
+		result[i] = fn([]interface{}{item})
+	}
+	return result
+}
+
+// arrayReduce调用JSONLang函数reducer(accumulator, item)，从initial开始依次折叠数组。
+func (gb *GoBackend) arrayReduce(args ...interface{}) interface{} {
+	if len(args) < 3 {
+		return fmt.Errorf("错误: array_reduce需要数组、函数名和初始值三个参数")
+	}
+	array, ok := args[0].([]interface{})
+	if !ok {
+		return fmt.Errorf("错误: 第一个参数必须是数组")
+	}
+	fn, err := gb.resolveCallback(args[1])
+	if err != nil {
+		return err
+	}
+
+	accumulator := args[2]
+	for _, item := range array {
+		accumulator = fn([]interface{}{accumulator, item})
+	}
+	return accumulator
+}
+
+// resolveCallback把一个JSONLang函数名解析成可以直接调用的Go闭包，
+// 供array_filter/array_map/array_reduce这类高阶函数复用。
+func (gb *GoBackend) resolveCallback(nameArg interface{}) (func(args []interface{}) interface{}, error) {
+	name := toString(nameArg)
+	if gb.program == nil {
+		return nil, fmt.Errorf("错误: 当前没有正在执行的程序，无法解析函数 '%s'", name)
+	}
+	if !gb.program.HasFunction(name) {
+		return nil, fmt.Errorf("错误: 函数 '%s' 未定义", name)
+	}
+	program := gb.program
+	return func(callArgs []interface{}) interface{} {
+		return executeFunction(program, gb, name, callArgs)
+	}, nil
+}
+
 func (gb *GoBackend) arrayReverse(args ...interface{}) interface{} {
 	if len(args) == 0 {
 		return []interface{}{}
@@ -896,7 +1224,7 @@ func toBoolean(value interface{}) bool {
 }
 
 // 运行JSON程序
-func runJSONProgram(filename string, backend *GoBackend) error {
+func runJSONProgram(filename string, registry *BackendRegistry) error {
 	// 读取JSON文件
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
@@ -917,6 +1245,17 @@ func runJSONProgram(filename string, backend *GoBackend) error {
 		return fmt.Errorf("程序缺少functions字段")
 	}
 
+	// 根据metadata.backend选择目标后端，未指定时默认使用Go后端
+	backendName, _ := program.Metadata["backend"].(string)
+	if backendName == "" {
+		backendName = "go"
+	}
+	backend, ok := registry.Create(backendName)
+	if !ok {
+		return fmt.Errorf("未知的后端: %s", backendName)
+	}
+	backend.SetProgram(program)
+
 	// 应用modifiers
 	applyModifiers(program)
 
@@ -930,109 +1269,26 @@ func runJSONProgram(filename string, backend *GoBackend) error {
 	result := executeFunction(program, backend, "main", []interface{}{})
 	fmt.Printf("程序执行完成，返回值: %v\n", result)
 
-	return nil
-}
-
-// 应用modifiers到所有函数
-func applyModifiers(program *JSONProgram) {
-	for funcName, funcData := range program.Functions {
-		// 获取函数的modifiers
-		if modifiers, ok := funcData["modifiers"].([]interface{}); ok {
-			// 应用每个modifier
-			for _, modifierName := range modifiers {
-				if name, ok := modifierName.(string); ok {
-					applyModifier(program, funcName, funcData, name)
-				}
-			}
-		}
-	}
-}
-
-// 应用单个modifier到函数
-func applyModifier(program *JSONProgram, funcName string, funcData map[string]interface{}, modifierName string) {
-	// 查找modifier定义
-	var modifier map[string]interface{}
-	for _, mod := range program.Modifiers {
-		if name, ok := mod["name"].(string); ok && name == modifierName {
-			modifier = mod
-			break
-		}
-	}
-
-	if modifier == nil {
-		fmt.Printf("警告: 找不到modifier '%s'\n", modifierName)
-		return
-	}
-
-	// 检查条件
-	if condition, ok := modifier["condiction"].(string); ok {
-		if !evaluateCondition(funcData, condition) {
-			return
-		}
-	}
-
-	// 执行actions
-	if actions, ok := modifier["actions"].([]interface{}); ok {
-		for _, action := range actions {
-			if actionMap, ok := action.(map[string]interface{}); ok {
-				executeModifierAction(funcData, actionMap)
-			}
-		}
-	}
-}
-
-// 评估modifier条件
-func evaluateCondition(funcData map[string]interface{}, condition string) bool {
-	// 简单的条件评估，支持基本的undefined检查
-	if strings.Contains(condition, "undefined") {
-		// 提取变量名
-		parts := strings.Split(condition, "==")
-		if len(parts) == 2 {
-			varName := strings.TrimSpace(parts[0])
-			switch varName {
-			case "function.args":
-				_, exists := funcData["args"]
-				return !exists
-			case "function.return":
-				_, exists := funcData["return"]
-				return !exists
-			case "function.modifiers":
-				_, exists := funcData["modifiers"]
-				return !exists
-			case "function.visibility":
-				_, exists := funcData["visibility"]
-				return !exists
-			}
-		}
+	if err, ok := result.(error); ok {
+		return err
 	}
-	return true // 默认返回True
+	return nil
 }
 
-// 执行modifier action
-func executeModifierAction(funcData map[string]interface{}, action map[string]interface{}) {
-	actionType, ok := action["type"].(string)
-	if !ok {
-		return
-	}
-
-	target, ok := action["target"].(string)
-	if !ok {
-		return
+// exitCodeForError把一个错误携带的HTTP语义状态码换算成shell退出码区间：
+// 5xx（解释器/后端故障）映射到2，4xx及其他任何error都映射到历史上的1。
+func exitCodeForError(err error) int {
+	if err == nil {
+		return 0
 	}
-
-	value := action["value"]
-
-	if actionType == "assignment" {
-		// 提取目标字段名
-		if strings.HasPrefix(target, "function.") {
-			fieldName := strings.Split(target, ".")[1]
-			funcData[fieldName] = value
-		}
+	if ce, ok := err.(*jsonlangerrors.CodedError); ok && ce.Coder.HTTPStatus() >= 500 {
+		return 2
 	}
+	return 1
 }
 
 // 执行函数
-func executeFunction(program *JSONProgram, backend *GoBackend, funcName string, args []interface{}) interface{} {
+func executeFunction(program *JSONProgram, backend Backend, funcName string, args []interface{}) interface{} {
 	funcData, exists := program.Functions[funcName]
 	if !exists {
 		return fmt.Errorf("函数 '%s' 未定义", funcName)
@@ -1044,45 +1300,34 @@ func executeFunction(program *JSONProgram, backend *GoBackend, funcName string,
 		return fmt.Errorf("函数 '%s' 缺少actions字段", funcName)
 	}
 
-	// 执行actions
-	var result interface{}
-	for _, action := range actions {
-		actionMap, ok := action.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		actionType, ok := actionMap["type"].(string)
-		if !ok {
-			continue
-		}
-
-		switch actionType {
-		case "function_call":
-			result = executeFunctionCall(program, backend, actionMap)
-		case "variable_declaration":
-			// 变量声明处理
-		case "assignment":
-			// 赋值处理
-		case "if_statement":
-			// 条件语句处理
-		case "loop":
-			// 循环处理
-		case "return":
-			// 返回处理
-		case "literal":
-			// 字面量处理
+	// 为本次调用建立一个新的作用域，并把params字段声明的形参绑定到传入的args上
+	scope := NewScope()
+	if params, ok := funcData["params"].([]interface{}); ok {
+		for i, p := range params {
+			name, ok := p.(string)
+			if !ok {
+				continue
+			}
+			var value interface{}
+			if i < len(args) {
+				value = args[i]
+			}
+			scope.Declare(name, value)
 		}
 	}
 
+	result := executeActionList(program, backend, scope, actions)
+	if rv, ok := result.(*returnValue); ok {
+		return rv.value
+	}
 	return result
 }
 
 // 执行函数调用
-func executeFunctionCall(program *JSONProgram, backend *GoBackend, action map[string]interface{}) interface{} {
+func executeFunctionCall(program *JSONProgram, backend Backend, scope *Scope, action map[string]interface{}) interface{} {
 	function, ok := action["function"].(string)
 	if !ok {
-		return fmt.Errorf("缺少function字段")
+		return jsonlangerrors.New(jsonlangerrors.ErrMissingFunctionField, "缺少function字段")
 	}
 
 	argsData, ok := action["args"].([]interface{})
@@ -1090,37 +1335,10 @@ func executeFunctionCall(program *JSONProgram, backend *GoBackend, action map[st
 		argsData = []interface{}{}
 	}
 
-	// 评估参数
+	// 评估参数：字面量、变量引用、嵌套函数调用统一通过evaluateExpression处理
 	args := make([]interface{}, len(argsData))
 	for i, argData := range argsData {
-		argMap, ok := argData.(map[string]interface{})
-		if !ok {
-			args[i] = argData
-			continue
-		}
-
-		argType, ok := argMap["type"].(string)
-		if !ok {
-			args[i] = argData
-			continue
-		}
-
-		switch argType {
-		case "String", "imports.String":
-			if value, ok := argMap["value"].(string); ok {
-				args[i] = value
-			}
-		case "Number", "imports.Number":
-			if value, ok := argMap["value"].(float64); ok {
-				args[i] = value
-			}
-		case "Boolean", "imports.Boolean":
-			if value, ok := argMap["value"].(bool); ok {
-				args[i] = value
-			}
-		default:
-			args[i] = argData
-		}
+		args[i] = evaluateExpression(program, backend, scope, argData)
 	}
 
 	// 检查是否是用户定义函数
@@ -1141,13 +1359,13 @@ func executeFunctionCall(program *JSONProgram, backend *GoBackend, action map[st
 				// 加载模块
 				moduleProgram, err := program.LoadModule(modulePath)
 				if err != nil {
-					return fmt.Errorf("导入模块失败: %v", err)
+					return jsonlangerrors.New(jsonlangerrors.ErrModuleLoadFailed, fmt.Sprintf("导入模块 '%s' 失败: %v", modulePath, err))
 				}
 
 				if moduleProgram.HasFunction(funcName) {
 					return executeFunction(moduleProgram, backend, funcName, args)
 				} else {
-					return fmt.Errorf("模块 '%s' 中没有函数 '%s'", modulePath, funcName)
+					return jsonlangerrors.New(jsonlangerrors.ErrFunctionNotInModule, fmt.Sprintf("模块 '%s' 中没有函数 '%s'", modulePath, funcName))
 				}
 			}
 		}
@@ -1192,13 +1410,13 @@ func executeFunctionCall(program *JSONProgram, backend *GoBackend, action map[st
 					// 加载模块
 					moduleProgram, err := program.LoadModule(modulePath)
 					if err != nil {
-						return fmt.Errorf("导入模块失败: %v", err)
+						return jsonlangerrors.New(jsonlangerrors.ErrModuleLoadFailed, fmt.Sprintf("导入模块 '%s' 失败: %v", modulePath, err))
 					}
 
 					if moduleProgram.HasFunction(actualFuncName) {
 						return executeFunction(moduleProgram, backend, actualFuncName, args)
 					} else {
-						return fmt.Errorf("模块 '%s' 中没有函数 '%s'", modulePath, actualFuncName)
+						return jsonlangerrors.New(jsonlangerrors.ErrFunctionNotInModule, fmt.Sprintf("模块 '%s' 中没有函数 '%s'", modulePath, actualFuncName))
 					}
 				}
 			}
@@ -1233,6 +1451,8 @@ func main() {
 		fmt.Println("  run <program.json>          运行JSON程序")
 		fmt.Println("  test <function> [args...]    测试函数")
 		fmt.Println("  list                         列出所有函数")
+		fmt.Println("  list-modules <program.json>  列出程序导入的第三方模块及其解析结果")
+		fmt.Println("  repl                         启动交互式REPL")
 		os.Exit(1)
 	}
 
@@ -1247,10 +1467,10 @@ func main() {
 		}
 		programFile := os.Args[2]
 
-		// 运行JSON程序
-		if err := runJSONProgram(programFile, backend); err != nil {
+		// 运行JSON程序，具体使用哪个后端由程序的metadata.backend字段决定
+		if err := runJSONProgram(programFile, defaultBackendRegistry); err != nil {
 			fmt.Printf("执行错误: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCodeForError(err))
 		}
 
 	case "test":
@@ -1284,6 +1504,25 @@ func main() {
 			fmt.Printf("  - %s\n", funcName)
 		}
 
+	case "repl":
+		if err := runREPL(); err != nil {
+			fmt.Printf("执行错误: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "list-modules":
+		if len(os.Args) < 3 {
+			fmt.Println("错误: 需要指定程序文件")
+			os.Exit(1)
+		}
+		programFile := os.Args[2]
+
+		fmt.Printf("导入模块 (来自 %s):\n", programFile)
+		if err := listModules(programFile); err != nil {
+			fmt.Printf("执行错误: %v\n", err)
+			os.Exit(1)
+		}
+
 	default:
 		fmt.Printf("错误: 未知命令 '%s'\n", command)
 		os.Exit(1)
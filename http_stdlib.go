@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// httpDoRequest 是http_get/http_post/http_request共用的底层实现，
+// 返回一个{status, headers, body}形式的map，与JSON程序中其它map值的表示保持一致。
+func (gb *GoBackend) httpDoRequest(method, url string, body string, headers map[string]interface{}) interface{} {
+	var bodyReader *strings.Reader
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	} else {
+		bodyReader = strings.NewReader("")
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(method), url, bodyReader)
+	if err != nil {
+		return fmt.Errorf("错误: 无法构造请求: %v", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, toString(value))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("错误: 请求 '%s' 失败: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("错误: 读取响应失败: %v", err)
+	}
+
+	respHeaders := make(map[string]interface{})
+	for key := range resp.Header {
+		respHeaders[key] = resp.Header.Get(key)
+	}
+
+	return map[string]interface{}{
+		"status":  float64(resp.StatusCode),
+		"headers": respHeaders,
+		"body":    string(respBody),
+	}
+}
+
+func (gb *GoBackend) httpHeadersArg(args []interface{}, index int) map[string]interface{} {
+	if len(args) <= index {
+		return nil
+	}
+	headers, _ := args[index].(map[string]interface{})
+	return headers
+}
+
+func (gb *GoBackend) httpGet(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: http_get需要一个url参数")
+	}
+	return gb.httpDoRequest("GET", toString(args[0]), "", gb.httpHeadersArg(args, 1))
+}
+
+func (gb *GoBackend) httpPost(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: http_post需要url和body两个参数")
+	}
+	return gb.httpDoRequest("POST", toString(args[0]), toString(args[1]), gb.httpHeadersArg(args, 2))
+}
+
+func (gb *GoBackend) httpRequest(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: http_request需要method和url两个参数")
+	}
+	body := ""
+	if len(args) > 2 {
+		body = toString(args[2])
+	}
+	return gb.httpDoRequest(toString(args[0]), toString(args[1]), body, gb.httpHeadersArg(args, 3))
+}
+
+// httpServerStart 启动一个HTTP服务器，路由表由之前的http_route调用填充。
+// 请求进来后根据路径分发给对应的JSONLang函数，请求本身被转换为一个map传入。
+func (gb *GoBackend) httpServerStart(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: http_server_start需要一个端口参数")
+	}
+	port := int(toNumber(args[0]))
+
+	mux := http.NewServeMux()
+	gb.httpRoutesMu.Lock()
+	for path, funcName := range gb.httpRoutes {
+		mux.HandleFunc(path, gb.makeHTTPHandler(funcName))
+	}
+	gb.httpRoutesMu.Unlock()
+
+	go func() {
+		http.ListenAndServe(fmt.Sprintf(":%d", port), mux)
+	}()
+	return nil
+}
+
+func (gb *GoBackend) makeHTTPHandler(funcName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if gb.program == nil || !gb.program.HasFunction(funcName) {
+			http.Error(w, fmt.Sprintf("函数 '%s' 未定义", funcName), http.StatusInternalServerError)
+			return
+		}
+		bodyBytes, _ := ioutil.ReadAll(r.Body)
+		headers := make(map[string]interface{})
+		for key := range r.Header {
+			headers[key] = r.Header.Get(key)
+		}
+		reqMap := map[string]interface{}{
+			"method":  r.Method,
+			"path":    r.URL.Path,
+			"host":    r.Host,
+			"headers": headers,
+			"body":    string(bodyBytes),
+		}
+		result := executeFunction(gb.program, gb, funcName, []interface{}{reqMap})
+		fmt.Fprint(w, toString(result))
+	}
+}
+
+func (gb *GoBackend) httpRoute(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: http_route需要path和funcName两个参数")
+	}
+	gb.httpRoutesMu.Lock()
+	gb.httpRoutes[toString(args[0])] = toString(args[1])
+	gb.httpRoutesMu.Unlock()
+	return nil
+}
+
+// httpGetHost 按照X-Forwarded-Host的约定解析客户端真实请求的host：
+// 如果头部存在，取逗号分隔链路中的最后一跳（离服务器最近的那个反向代理写入的值），
+// 否则回退到req.Host。
+func (gb *GoBackend) httpGetHost(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: http_get_host需要一个请求参数")
+	}
+	req, ok := args[0].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("错误: 参数必须是一个请求map")
+	}
+	headers, _ := req["headers"].(map[string]interface{})
+	if forwarded, ok := headers["X-Forwarded-Host"]; ok {
+		hops := strings.Split(toString(forwarded), ",")
+		last := strings.TrimSpace(hops[len(hops)-1])
+		if last != "" {
+			return last
+		}
+	}
+	return toString(req["host"])
+}
+
+func (gb *GoBackend) httpGetDomain(args ...interface{}) interface{} {
+	host := toString(gb.httpGetHost(args...))
+	if idx := strings.Index(host, ":"); idx != -1 {
+		return host[:idx]
+	}
+	return host
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	jsonlangerrors "github.com/Llonvne/jsonlang/errors"
+)
+
+// JSONError是后端函数向JSONLang程序报告失败时使用的结构化错误值，
+// 取代了此前与正常返回值混在一起、无法被区分的fmt.Errorf。
+type JSONError struct {
+	Code    string
+	Message string
+	Data    map[string]interface{}
+}
+
+func NewJSONError(code, message string, data map[string]interface{}) *JSONError {
+	return &JSONError{Code: code, Message: message, Data: data}
+}
+
+func (e *JSONError) Error() string {
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+}
+
+// isError判断一个值是否代表一次失败，既识别新的*JSONError，也兼容
+// 仍然到处存在的fmt.Errorf返回值。
+func isError(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	_, ok := value.(error)
+	return ok
+}
+
+func (gb *GoBackend) isError(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return false
+	}
+	return isError(args[0])
+}
+
+func (gb *GoBackend) errorCode(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return ""
+	}
+	if je, ok := args[0].(*JSONError); ok {
+		return je.Code
+	}
+	if ce, ok := args[0].(*jsonlangerrors.CodedError); ok {
+		return strconv.Itoa(ce.Coder.Code())
+	}
+	if isError(args[0]) {
+		return "UNKNOWN"
+	}
+	return ""
+}
+
+// errorHTTPStatus返回一个*jsonlangerrors.CodedError携带的Coder.HTTPStatus()，
+// 对其他错误类型（包括*JSONError这类业务错误）返回0表示没有对应的HTTP语义。
+func (gb *GoBackend) errorHTTPStatus(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return float64(0)
+	}
+	if ce, ok := args[0].(*jsonlangerrors.CodedError); ok {
+		return float64(ce.Coder.HTTPStatus())
+	}
+	return float64(0)
+}
+
+// errorReference返回一个*jsonlangerrors.CodedError携带的排障参考链接。
+func (gb *GoBackend) errorReference(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return ""
+	}
+	if ce, ok := args[0].(*jsonlangerrors.CodedError); ok {
+		return ce.Coder.Reference()
+	}
+	return ""
+}
+
+func (gb *GoBackend) errorMessage(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return ""
+	}
+	if err, ok := args[0].(error); ok {
+		return err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,495 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 应用modifiers到所有函数
+func applyModifiers(program *JSONProgram) {
+	for funcName, funcData := range program.Functions {
+		// 获取函数的modifiers
+		if modifiers, ok := funcData["modifiers"].([]interface{}); ok {
+			// 应用每个modifier
+			for _, modifierName := range modifiers {
+				if name, ok := modifierName.(string); ok {
+					applyModifier(program, funcName, funcData, name)
+				}
+			}
+		}
+	}
+}
+
+// 应用单个modifier到函数
+func applyModifier(program *JSONProgram, funcName string, funcData map[string]interface{}, modifierName string) {
+	// 查找modifier定义
+	var modifier map[string]interface{}
+	for _, mod := range program.Modifiers {
+		if name, ok := mod["name"].(string); ok && name == modifierName {
+			modifier = mod
+			break
+		}
+	}
+
+	if modifier == nil {
+		fmt.Printf("警告: 找不到modifier '%s'\n", modifierName)
+		return
+	}
+
+	// 检查条件
+	if condition, ok := modifier["condiction"].(string); ok {
+		if !evaluateCondition(funcData, condition) {
+			return
+		}
+	}
+
+	// 执行actions
+	if actions, ok := modifier["actions"].([]interface{}); ok {
+		for _, action := range actions {
+			if actionMap, ok := action.(map[string]interface{}); ok {
+				executeModifierAction(funcData, actionMap)
+			}
+		}
+	}
+}
+
+// 执行modifier action
+func executeModifierAction(funcData map[string]interface{}, action map[string]interface{}) {
+	actionType, ok := action["type"].(string)
+	if !ok {
+		return
+	}
+
+	switch actionType {
+	case "assignment":
+		target, ok := action["target"].(string)
+		if !ok {
+			return
+		}
+		if strings.HasPrefix(target, "function.") {
+			fieldName := strings.Split(target, ".")[1]
+			funcData[fieldName] = action["value"]
+		}
+	case "append":
+		appendToField(funcData, action, true)
+	case "prepend":
+		appendToField(funcData, action, false)
+	case "delete":
+		target, ok := action["target"].(string)
+		if !ok {
+			return
+		}
+		fieldName := strings.TrimPrefix(target, "function.")
+		delete(funcData, fieldName)
+	case "wrap":
+		wrapActions(funcData, action)
+	}
+}
+
+// appendToField把action["value"]追加（或前置）到target指定的数组字段上，
+// value既可以是单个元素，也可以是一个数组（此时整体展开合并）。
+func appendToField(funcData map[string]interface{}, action map[string]interface{}, atEnd bool) {
+	target, ok := action["target"].(string)
+	if !ok {
+		return
+	}
+	fieldName := strings.TrimPrefix(target, "function.")
+	existing, _ := funcData[fieldName].([]interface{})
+
+	var additions []interface{}
+	if values, ok := action["value"].([]interface{}); ok {
+		additions = values
+	} else {
+		additions = []interface{}{action["value"]}
+	}
+
+	if atEnd {
+		funcData[fieldName] = append(append([]interface{}{}, existing...), additions...)
+	} else {
+		funcData[fieldName] = append(append([]interface{}{}, additions...), existing...)
+	}
+}
+
+// wrapActions把函数现有的actions包裹进一个新的序列里，前后各插入value.before/value.after
+// 指定的action列表，从而实现before/after风格的装饰器。
+func wrapActions(funcData map[string]interface{}, action map[string]interface{}) {
+	originalActions, _ := funcData["actions"].([]interface{})
+
+	var before, after []interface{}
+	if value, ok := action["value"].(map[string]interface{}); ok {
+		before, _ = value["before"].([]interface{})
+		after, _ = value["after"].([]interface{})
+	}
+
+	wrapped := make([]interface{}, 0, len(before)+len(originalActions)+len(after))
+	wrapped = append(wrapped, before...)
+	wrapped = append(wrapped, originalActions...)
+	wrapped = append(wrapped, after...)
+	funcData["actions"] = wrapped
+}
+
+// undefinedType是表达式求值中"不存在"的哨兵值，与JSON的null（nil）是两回事：
+// null是显式存在的空值，undefined是字段根本没有出现过。
+type undefinedType struct{}
+
+var undefinedValue = undefinedType{}
+
+// conditionExpr是modifier条件表达式解析后的抽象语法树节点。
+type conditionExpr interface {
+	eval(funcData map[string]interface{}) interface{}
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+func (e *literalExpr) eval(funcData map[string]interface{}) interface{} {
+	return e.value
+}
+
+type identifierExpr struct {
+	path string
+}
+
+func (e *identifierExpr) eval(funcData map[string]interface{}) interface{} {
+	return resolveIdentifier(funcData, e.path)
+}
+
+type unaryNotExpr struct {
+	operand conditionExpr
+}
+
+func (e *unaryNotExpr) eval(funcData map[string]interface{}) interface{} {
+	return !conditionTruthy(e.operand.eval(funcData))
+}
+
+type binaryExpr struct {
+	op          string
+	left, right conditionExpr
+}
+
+func (e *binaryExpr) eval(funcData map[string]interface{}) interface{} {
+	switch e.op {
+	case "&&":
+		if !conditionTruthy(e.left.eval(funcData)) {
+			return false
+		}
+		return conditionTruthy(e.right.eval(funcData))
+	case "||":
+		if conditionTruthy(e.left.eval(funcData)) {
+			return true
+		}
+		return conditionTruthy(e.right.eval(funcData))
+	case "==":
+		return valuesEqual(e.left.eval(funcData), e.right.eval(funcData))
+	case "!=":
+		return !valuesEqual(e.left.eval(funcData), e.right.eval(funcData))
+	case "<":
+		return toNumber(e.left.eval(funcData)) < toNumber(e.right.eval(funcData))
+	case ">":
+		return toNumber(e.left.eval(funcData)) > toNumber(e.right.eval(funcData))
+	case "in":
+		return containsValue(e.right.eval(funcData), e.left.eval(funcData))
+	case "has":
+		return containsValue(e.left.eval(funcData), e.right.eval(funcData))
+	default:
+		return false
+	}
+}
+
+// resolveIdentifier解析形如function.args、function.modifiers.length的路径。
+// 缺失的字段返回undefinedValue，而不是nil，以便与JSON中显式的null区分开。
+func resolveIdentifier(funcData map[string]interface{}, path string) interface{} {
+	parts := strings.Split(path, ".")
+	if len(parts) > 0 && parts[0] == "function" {
+		parts = parts[1:]
+	}
+	if len(parts) == 0 {
+		return undefinedValue
+	}
+
+	if len(parts) >= 2 && parts[len(parts)-1] == "length" {
+		base := navigateFuncData(funcData, parts[:len(parts)-1])
+		return lengthOf(base)
+	}
+	return navigateFuncData(funcData, parts)
+}
+
+func navigateFuncData(funcData map[string]interface{}, parts []string) interface{} {
+	var current interface{} = funcData
+	for _, part := range parts {
+		asMap, ok := current.(map[string]interface{})
+		if !ok {
+			return undefinedValue
+		}
+		value, exists := asMap[part]
+		if !exists {
+			return undefinedValue
+		}
+		current = value
+	}
+	return current
+}
+
+func lengthOf(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return float64(len(v))
+	case map[string]interface{}:
+		return float64(len(v))
+	case string:
+		return float64(len(v))
+	default:
+		return undefinedValue
+	}
+}
+
+func conditionTruthy(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if _, ok := value.(undefinedType); ok {
+		return false
+	}
+	return toBoolean(value)
+}
+
+func valuesEqual(a, b interface{}) bool {
+	_, aUndefined := a.(undefinedType)
+	_, bUndefined := b.(undefinedType)
+	if aUndefined || bUndefined {
+		return aUndefined && bUndefined
+	}
+	if af, aOk := a.(float64); aOk {
+		if bf, bOk := b.(float64); bOk {
+			return af == bf
+		}
+	}
+	if ab, aOk := a.(bool); aOk {
+		if bb, bOk := b.(bool); bOk {
+			return ab == bb
+		}
+	}
+	return toString(a) == toString(b)
+}
+
+func containsValue(collection, item interface{}) bool {
+	switch c := collection.(type) {
+	case []interface{}:
+		for _, elem := range c {
+			if valuesEqual(elem, item) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		_, exists := c[toString(item)]
+		return exists
+	default:
+		return false
+	}
+}
+
+// modifierToken是条件表达式词法分析产生的单个记号。
+type modifierToken struct {
+	kind string // "ident"、"string"、"number"、"op"
+	text string
+}
+
+// lexModifierCondition把条件字符串切分成记号流，支持带引号的字符串字面量、
+// 数字、标识符（含点号路径），以及==、!=、<、>、&&、||、!、(、)几类运算符。
+func lexModifierCondition(condition string) []modifierToken {
+	var tokens []modifierToken
+	runes := []rune(condition)
+	i := 0
+	for i < len(runes) {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			quote := c
+			j := i + 1
+			for j < len(runes) && runes[j] != quote {
+				j++
+			}
+			tokens = append(tokens, modifierToken{kind: "string", text: string(runes[i+1 : j])})
+			i = j + 1
+		case c == '(' || c == ')':
+			tokens = append(tokens, modifierToken{kind: "op", text: string(c)})
+			i++
+		case c == '=' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, modifierToken{kind: "op", text: "=="})
+			i += 2
+		case c == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, modifierToken{kind: "op", text: "!="})
+			i += 2
+		case c == '&' && i+1 < len(runes) && runes[i+1] == '&':
+			tokens = append(tokens, modifierToken{kind: "op", text: "&&"})
+			i += 2
+		case c == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			tokens = append(tokens, modifierToken{kind: "op", text: "||"})
+			i += 2
+		case c == '!':
+			tokens = append(tokens, modifierToken{kind: "op", text: "!"})
+			i++
+		case c == '<':
+			tokens = append(tokens, modifierToken{kind: "op", text: "<"})
+			i++
+		case c == '>':
+			tokens = append(tokens, modifierToken{kind: "op", text: ">"})
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(runes) && (runes[j] >= '0' && runes[j] <= '9' || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, modifierToken{kind: "number", text: string(runes[i:j])})
+			i = j
+		default:
+			j := i
+			for j < len(runes) && isIdentRune(runes[j]) {
+				j++
+			}
+			if j == i {
+				// 无法识别的字符，跳过以避免死循环
+				i++
+				continue
+			}
+			tokens = append(tokens, modifierToken{kind: "ident", text: string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens
+}
+
+func isIdentRune(r rune) bool {
+	return r == '.' || r == '_' ||
+		(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// modifierParser是条件表达式的手写递归下降解析器。
+// 优先级从低到高依次是: || , && , 一元! , 比较运算符(== != < > in has)。
+type modifierParser struct {
+	tokens []modifierToken
+	pos    int
+}
+
+func (p *modifierParser) peek() (modifierToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return modifierToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *modifierParser) next() (modifierToken, bool) {
+	tok, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return tok, ok
+}
+
+func (p *modifierParser) parseOr() conditionExpr {
+	left := p.parseAnd()
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.text != "||" {
+			break
+		}
+		p.next()
+		right := p.parseAnd()
+		left = &binaryExpr{op: "||", left: left, right: right}
+	}
+	return left
+}
+
+func (p *modifierParser) parseAnd() conditionExpr {
+	left := p.parseUnary()
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.text != "&&" {
+			break
+		}
+		p.next()
+		right := p.parseUnary()
+		left = &binaryExpr{op: "&&", left: left, right: right}
+	}
+	return left
+}
+
+func (p *modifierParser) parseUnary() conditionExpr {
+	if tok, ok := p.peek(); ok && tok.text == "!" {
+		p.next()
+		return &unaryNotExpr{operand: p.parseUnary()}
+	}
+	return p.parseComparison()
+}
+
+func (p *modifierParser) parseComparison() conditionExpr {
+	left := p.parsePrimary()
+	tok, ok := p.peek()
+	if !ok {
+		return left
+	}
+	switch tok.text {
+	case "==", "!=", "<", ">", "in", "has":
+		p.next()
+		right := p.parsePrimary()
+		return &binaryExpr{op: tok.text, left: left, right: right}
+	}
+	return left
+}
+
+func (p *modifierParser) parsePrimary() conditionExpr {
+	tok, ok := p.next()
+	if !ok {
+		return &literalExpr{value: undefinedValue}
+	}
+	switch tok.kind {
+	case "string":
+		return &literalExpr{value: tok.text}
+	case "number":
+		n, _ := strconv.ParseFloat(tok.text, 64)
+		return &literalExpr{value: n}
+	case "op":
+		if tok.text == "(" {
+			inner := p.parseOr()
+			if next, ok := p.peek(); ok && next.text == ")" {
+				p.next()
+			}
+			return inner
+		}
+		return &literalExpr{value: undefinedValue}
+	default: // ident
+		switch tok.text {
+		case "undefined":
+			return &literalExpr{value: undefinedValue}
+		case "true":
+			return &literalExpr{value: true}
+		case "false":
+			return &literalExpr{value: false}
+		default:
+			return &identifierExpr{path: tok.text}
+		}
+	}
+}
+
+// evaluateCondition解析并求值一个modifier条件表达式，支持
+// function.args / function.return / function.visibility / function.modifiers.length
+// 等标识符、字符串/数字/布尔字面量，以及 == != < > && || ! in has 运算符。
+// 空条件视为始终满足。
+func evaluateCondition(funcData map[string]interface{}, condition string) bool {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return true
+	}
+	tokens := lexModifierCondition(condition)
+	if len(tokens) == 0 {
+		return true
+	}
+	parser := &modifierParser{tokens: tokens}
+	expr := parser.parseOr()
+	return conditionTruthy(expr.eval(funcData))
+}
@@ -0,0 +1,119 @@
+package main
+
+import "testing"
+
+func TestEvaluateConditionUndefinedChecks(t *testing.T) {
+	funcData := map[string]interface{}{
+		"visibility": "private",
+	}
+
+	if !evaluateCondition(funcData, "function.args == undefined") {
+		t.Errorf("expected function.args == undefined to be true when args is missing")
+	}
+	if evaluateCondition(funcData, "function.visibility == undefined") {
+		t.Errorf("expected function.visibility == undefined to be false when visibility is set")
+	}
+}
+
+func TestEvaluateConditionCompound(t *testing.T) {
+	funcData := map[string]interface{}{
+		"visibility": "private",
+	}
+
+	if evaluateCondition(funcData, `function.args == undefined && function.visibility != "private"`) {
+		t.Errorf("expected compound condition to be false because visibility is private")
+	}
+
+	funcData["visibility"] = "public"
+	if !evaluateCondition(funcData, `function.args == undefined && function.visibility != "private"`) {
+		t.Errorf("expected compound condition to be true once visibility is public")
+	}
+}
+
+func TestEvaluateConditionOrAndNot(t *testing.T) {
+	funcData := map[string]interface{}{
+		"visibility": "public",
+	}
+
+	if !evaluateCondition(funcData, `function.visibility == "private" || function.visibility == "public"`) {
+		t.Errorf("expected || condition to be true")
+	}
+	if !evaluateCondition(funcData, `!(function.visibility == "private")`) {
+		t.Errorf("expected negated condition to be true")
+	}
+}
+
+func TestEvaluateConditionModifiersLength(t *testing.T) {
+	funcData := map[string]interface{}{
+		"modifiers": []interface{}{"logged", "cached"},
+	}
+
+	if !evaluateCondition(funcData, "function.modifiers.length > 1") {
+		t.Errorf("expected function.modifiers.length > 1 to be true")
+	}
+	if evaluateCondition(funcData, "function.modifiers.length > 5") {
+		t.Errorf("expected function.modifiers.length > 5 to be false")
+	}
+}
+
+func TestEvaluateConditionInAndHas(t *testing.T) {
+	funcData := map[string]interface{}{
+		"modifiers": []interface{}{"logged", "cached"},
+	}
+
+	if !evaluateCondition(funcData, `"logged" in function.modifiers`) {
+		t.Errorf(`expected "logged" in function.modifiers to be true`)
+	}
+	if !evaluateCondition(funcData, `function.modifiers has "cached"`) {
+		t.Errorf(`expected function.modifiers has "cached" to be true`)
+	}
+	if evaluateCondition(funcData, `"missing" in function.modifiers`) {
+		t.Errorf(`expected "missing" in function.modifiers to be false`)
+	}
+}
+
+func TestExecuteModifierActionAppendPrependDeleteWrap(t *testing.T) {
+	funcData := map[string]interface{}{
+		"actions": []interface{}{"core"},
+	}
+
+	executeModifierAction(funcData, map[string]interface{}{
+		"type":   "append",
+		"target": "function.actions",
+		"value":  "after",
+	})
+	actions := funcData["actions"].([]interface{})
+	if len(actions) != 2 || actions[1] != "after" {
+		t.Fatalf("expected append to add 'after' at the end, got %v", actions)
+	}
+
+	executeModifierAction(funcData, map[string]interface{}{
+		"type":   "prepend",
+		"target": "function.actions",
+		"value":  "before",
+	})
+	actions = funcData["actions"].([]interface{})
+	if len(actions) != 3 || actions[0] != "before" {
+		t.Fatalf("expected prepend to add 'before' at the start, got %v", actions)
+	}
+
+	executeModifierAction(funcData, map[string]interface{}{
+		"type": "wrap",
+		"value": map[string]interface{}{
+			"before": []interface{}{"log_enter"},
+			"after":  []interface{}{"log_exit"},
+		},
+	})
+	actions = funcData["actions"].([]interface{})
+	if len(actions) != 5 || actions[0] != "log_enter" || actions[len(actions)-1] != "log_exit" {
+		t.Fatalf("expected wrap to add before/after decorators, got %v", actions)
+	}
+
+	executeModifierAction(funcData, map[string]interface{}{
+		"type":   "delete",
+		"target": "function.actions",
+	})
+	if _, exists := funcData["actions"]; exists {
+		t.Fatalf("expected delete to remove the actions field")
+	}
+}
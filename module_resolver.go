@@ -0,0 +1,286 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ModuleResolver负责把一个模块路径（形如"github.com/foo/bar"）解析成可读取的JSON源码，
+// 具体来源可以是本地磁盘、HTTP代理、或其他任何实现了这个接口的来源。
+type ModuleResolver interface {
+	Resolve(path string) (io.ReadCloser, error)
+}
+
+// localModuleResolver在一组根目录下查找模块文件，根目录来自JSONLANG_PATH环境变量
+// （用os.PathListSeparator分隔多个根，类比Go的GOPATH），当前目录总是作为兜底根。
+type localModuleResolver struct {
+	roots []string
+}
+
+func newLocalModuleResolver() *localModuleResolver {
+	var roots []string
+	if jsonlangPath := os.Getenv("JSONLANG_PATH"); jsonlangPath != "" {
+		roots = strings.Split(jsonlangPath, string(os.PathListSeparator))
+	}
+	roots = append(roots, ".")
+	return &localModuleResolver{roots: roots}
+}
+
+func (r *localModuleResolver) Resolve(path string) (io.ReadCloser, error) {
+	last := path
+	if idx := strings.LastIndex(path, "."); idx >= 0 {
+		last = path[idx+1:]
+	}
+	candidates := []string{path + ".json", path, last + ".json", last}
+
+	for _, root := range r.roots {
+		for _, candidate := range candidates {
+			full := filepath.Join(root, candidate)
+			if _, err := os.Stat(full); err == nil {
+				return os.Open(full)
+			}
+		}
+	}
+	return nil, fmt.Errorf("在JSONLANG_PATH中找不到模块文件: %s", path)
+}
+
+// httpModuleResolver模仿GOPROXY=host1,host2,direct的逗号分隔回退约定：依次尝试
+// 每一个代理host，遇到字面量"direct"就改为从模块路径派生的origin URL直接拉取。
+type httpModuleResolver struct {
+	proxies []string
+	client  *http.Client
+}
+
+func newHTTPModuleResolver(proxyList string) *httpModuleResolver {
+	var proxies []string
+	for _, p := range strings.Split(proxyList, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return &httpModuleResolver{proxies: proxies, client: &http.Client{}}
+}
+
+func (r *httpModuleResolver) Resolve(path string) (io.ReadCloser, error) {
+	var lastErr error
+	for _, proxy := range r.proxies {
+		var url string
+		if proxy == "direct" {
+			url = "https://" + path + ".json"
+		} else {
+			url = strings.TrimSuffix(proxy, "/") + "/" + path + ".json"
+		}
+
+		resp, err := r.client.Get(url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("代理 '%s' 返回状态码 %d", proxy, resp.StatusCode)
+			continue
+		}
+		return resp.Body, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("没有配置任何代理")
+	}
+	return nil, fmt.Errorf("从所有代理获取模块 '%s' 失败: %v", path, lastErr)
+}
+
+// chainModuleResolver依次尝试多个ModuleResolver，前一个解析失败就退回到下一个，
+// 对应"先查本地JSONLANG_PATH，再退回到远程代理"的整体解析策略。
+type chainModuleResolver struct {
+	resolvers []ModuleResolver
+}
+
+func (c *chainModuleResolver) Resolve(path string) (io.ReadCloser, error) {
+	var lastErr error
+	for _, r := range c.resolvers {
+		reader, err := r.Resolve(path)
+		if err == nil {
+			return reader, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+const defaultModuleCacheCapacity = 128
+
+// defaultModuleResolver是未显式调用SetModuleResolver时的解析策略：本地优先，
+// 找不到再按JSONLANG_PROXY（默认"direct"）描述的代理列表尝试远程获取。
+func defaultModuleResolver() ModuleResolver {
+	proxyList := os.Getenv("JSONLANG_PROXY")
+	if proxyList == "" {
+		proxyList = "direct"
+	}
+	return &chainModuleResolver{
+		resolvers: []ModuleResolver{
+			newLocalModuleResolver(),
+			newHTTPModuleResolver(proxyList),
+		},
+	}
+}
+
+// moduleCacheEntry是LRU缓存中的一项，contentHash用于在同一模块路径对应的内容
+// 发生变化时使旧的解析结果失效，类比GOPROXY的内容寻址缓存。
+type moduleCacheEntry struct {
+	contentHash string
+	program     *JSONProgram
+}
+
+// moduleLRUCache是一个按模块路径+内容哈希寻址的内存LRU缓存，避免同一份模块源码
+// 被反复解析；查缓存前源码已经读取完毕，所以它不会省掉下载。
+type moduleLRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []string
+	items    map[string]*moduleCacheEntry
+}
+
+func newModuleLRUCache(capacity int) *moduleLRUCache {
+	return &moduleLRUCache{
+		capacity: capacity,
+		items:    make(map[string]*moduleCacheEntry),
+	}
+}
+
+func (c *moduleLRUCache) get(path, contentHash string) (*JSONProgram, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.items[path]
+	if !ok || entry.contentHash != contentHash {
+		return nil, false
+	}
+	c.touch(path)
+	return entry.program, true
+}
+
+func (c *moduleLRUCache) put(path, contentHash string, program *JSONProgram) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.items[path]; !exists {
+		if len(c.order) >= c.capacity {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.items, oldest)
+		}
+		c.order = append(c.order, path)
+	} else {
+		c.touch(path)
+	}
+	c.items[path] = &moduleCacheEntry{contentHash: contentHash, program: program}
+}
+
+// touch把path移到order末尾以标记其为最近使用；调用方必须已持有c.mu。
+func (c *moduleLRUCache) touch(path string) {
+	for i, p := range c.order {
+		if p == path {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, path)
+}
+
+// loadModuleEntry是LoadModule的实际实现，额外返回这次调用是否命中了缓存
+// （模块已在LoadedModules中，或命中了内容寻址缓存），供list-modules子命令展示模块解析来源。
+func (jp *JSONProgram) loadModuleEntry(modulePath string) (*JSONProgram, bool, error) {
+	if module, exists := jp.LoadedModules[modulePath]; exists {
+		return module, true, nil
+	}
+
+	if jp.Resolver == nil {
+		jp.Resolver = defaultModuleResolver()
+	}
+	if jp.moduleCache == nil {
+		jp.moduleCache = newModuleLRUCache(defaultModuleCacheCapacity)
+	}
+
+	reader, err := jp.Resolver.Resolve(modulePath)
+	if err != nil {
+		return nil, false, fmt.Errorf("解析模块 '%s' 失败: %v", modulePath, err)
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("读取模块 '%s' 失败: %v", modulePath, err)
+	}
+
+	contentHash := fmt.Sprintf("%x", sha256.Sum256(data))
+	if cached, ok := jp.moduleCache.get(modulePath, contentHash); ok {
+		jp.LoadedModules[modulePath] = cached
+		return cached, true, nil
+	}
+
+	var moduleData map[string]interface{}
+	if err := json.Unmarshal(data, &moduleData); err != nil {
+		return nil, false, fmt.Errorf("模块文件JSON格式错误: %v", err)
+	}
+
+	moduleProgram := NewJSONProgram(moduleData)
+	moduleProgram.Resolver = jp.Resolver
+	jp.moduleCache.put(modulePath, contentHash, moduleProgram)
+	jp.LoadedModules[modulePath] = moduleProgram
+
+	return moduleProgram, false, nil
+}
+
+// SetModuleResolver覆盖当前已绑定程序（及其后续加载的模块）的模块解析策略，
+// 比如在CLI中注入一个只读本地目录或企业内网代理。
+func (gb *GoBackend) SetModuleResolver(resolver ModuleResolver) {
+	if gb.program != nil {
+		gb.program.Resolver = resolver
+	}
+}
+
+// listModules加载给定JSON程序的所有第三方模块import，并打印每一个的解析路径
+// 以及这次解析是否命中了缓存（已加载的模块或LRU缓存），便于诊断import配置。
+func listModules(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("无法读取文件 '%s': %v", filename, err)
+	}
+
+	var programData map[string]interface{}
+	if err := json.Unmarshal(data, &programData); err != nil {
+		return fmt.Errorf("JSON格式错误: %v", err)
+	}
+
+	program := NewJSONProgram(programData)
+
+	for alias, importPath := range program.Imports {
+		if !strings.Contains(importPath, ".") || strings.HasPrefix(importPath, "jsonlang.") {
+			continue
+		}
+
+		parts := strings.Split(importPath, ".")
+		modulePath := strings.Join(parts[:len(parts)-1], ".")
+
+		_, cacheHit, err := program.loadModuleEntry(modulePath)
+		if err != nil {
+			fmt.Printf("  %s -> %s: 解析失败 (%v)\n", alias, modulePath, err)
+			continue
+		}
+
+		status := "已解析"
+		if cacheHit {
+			status = "缓存命中"
+		}
+		fmt.Printf("  %s -> %s: %s\n", alias, modulePath, status)
+	}
+
+	return nil
+}
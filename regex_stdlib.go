@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// regexCache缓存已编译的正则表达式，避免同一模式在热路径上反复编译。
+type regexCache struct {
+	mu    sync.RWMutex
+	items map[string]*regexp.Regexp
+}
+
+func newRegexCache() *regexCache {
+	return &regexCache{
+		items: make(map[string]*regexp.Regexp),
+	}
+}
+
+func (rc *regexCache) compile(pattern string) (*regexp.Regexp, error) {
+	rc.mu.RLock()
+	re, ok := rc.items[pattern]
+	rc.mu.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.mu.Lock()
+	rc.items[pattern] = re
+	rc.mu.Unlock()
+	return re, nil
+}
+
+// resolveRegex接受一个模式字符串，或者由regex_compile返回的句柄，统一解析成*regexp.Regexp。
+func (gb *GoBackend) resolveRegex(arg interface{}) (*regexp.Regexp, error) {
+	if handle, ok := arg.(string); ok {
+		if value, exists := gb.handles.load(handle); exists {
+			if re, ok := value.(*regexp.Regexp); ok {
+				return re, nil
+			}
+		}
+	}
+	return gb.regexCache.compile(toString(arg))
+}
+
+func (gb *GoBackend) regexCompile(args ...interface{}) interface{} {
+	if len(args) == 0 {
+		return fmt.Errorf("错误: regex_compile需要一个模式参数")
+	}
+	re, err := gb.regexCache.compile(toString(args[0]))
+	if err != nil {
+		return fmt.Errorf("错误: 无效的正则表达式 '%s': %v", toString(args[0]), err)
+	}
+	return gb.handles.store(re)
+}
+
+func (gb *GoBackend) regexMatch(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: regex_match需要pattern和字符串两个参数")
+	}
+	re, err := gb.resolveRegex(args[0])
+	if err != nil {
+		return fmt.Errorf("错误: 无效的正则表达式: %v", err)
+	}
+	return re.MatchString(toString(args[1]))
+}
+
+func (gb *GoBackend) regexFind(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: regex_find需要pattern和字符串两个参数")
+	}
+	re, err := gb.resolveRegex(args[0])
+	if err != nil {
+		return fmt.Errorf("错误: 无效的正则表达式: %v", err)
+	}
+	return re.FindString(toString(args[1]))
+}
+
+func (gb *GoBackend) regexFindAll(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: regex_find_all需要pattern和字符串两个参数")
+	}
+	re, err := gb.resolveRegex(args[0])
+	if err != nil {
+		return fmt.Errorf("错误: 无效的正则表达式: %v", err)
+	}
+	matches := re.FindAllString(toString(args[1]), -1)
+	result := make([]interface{}, len(matches))
+	for i, m := range matches {
+		result[i] = m
+	}
+	return result
+}
+
+func (gb *GoBackend) regexReplace(args ...interface{}) interface{} {
+	if len(args) < 3 {
+		return fmt.Errorf("错误: regex_replace需要pattern、字符串和替换内容三个参数")
+	}
+	re, err := gb.resolveRegex(args[0])
+	if err != nil {
+		return fmt.Errorf("错误: 无效的正则表达式: %v", err)
+	}
+	return re.ReplaceAllString(toString(args[1]), toString(args[2]))
+}
+
+func (gb *GoBackend) regexSplit(args ...interface{}) interface{} {
+	if len(args) < 2 {
+		return fmt.Errorf("错误: regex_split需要pattern和字符串两个参数")
+	}
+	re, err := gb.resolveRegex(args[0])
+	if err != nil {
+		return fmt.Errorf("错误: 无效的正则表达式: %v", err)
+	}
+	parts := re.Split(toString(args[1]), -1)
+	result := make([]interface{}, len(parts))
+	for i, p := range parts {
+		result[i] = p
+	}
+	return result
+}
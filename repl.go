@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runREPL启动一个交互式读取-求值-打印循环：每一行被解析成一个action
+// （完整的JSON action，或者funcName(arg1, arg2)这种简写），在一个常驻的
+// GoBackend和顶层Scope上执行，使得一行里声明的变量能在后续的行里继续使用。
+//
+// 历史记录持久化到~/.jsonlang_history。这里没有引入第三方readline库，
+// 所以这里的tab补全是cooked终端模式下能做到的最朴素形式：一行的末尾如果
+// 是制表符，就把它之前的内容当作补全前缀，列出匹配的函数名/已绑定变量名
+// 供参考，而不是像真正的readline那样原地替换当前行。
+func runREPL() error {
+	backend := NewGoBackend()
+	program := NewJSONProgram(map[string]interface{}{})
+	backend.SetProgram(program)
+	scope := NewScope()
+
+	historyPath := historyFilePath()
+
+	fmt.Println("jsonlang REPL - 输入exit或quit退出，行尾加Tab列出补全建议")
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print(">>> ")
+		if !scanner.Scan() {
+			break
+		}
+		rawLine := scanner.Text()
+
+		if strings.HasSuffix(rawLine, "\t") {
+			printCompletions(strings.TrimSuffix(rawLine, "\t"), backend, scope)
+			continue
+		}
+
+		line := strings.TrimSpace(rawLine)
+		if line == "" {
+			continue
+		}
+		if line == "exit" || line == "quit" {
+			break
+		}
+
+		appendHistory(historyPath, line)
+
+		action, err := parseREPLLine(line)
+		if err != nil {
+			fmt.Printf("解析错误: %v\n", err)
+			continue
+		}
+
+		result := executeActionList(program, backend, scope, []interface{}{action})
+		if rv, ok := result.(*returnValue); ok {
+			result = rv.value
+		}
+		fmt.Printf("=> %v\n", result)
+	}
+
+	return nil
+}
+
+// parseREPLLine把一行输入解析成一个action：以'{'开头的当作完整JSON action，
+// 否则按funcName(arg1, arg2)的简写形式解析成一个function_call action。
+func parseREPLLine(line string) (map[string]interface{}, error) {
+	if strings.HasPrefix(line, "{") {
+		var action map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &action); err != nil {
+			return nil, fmt.Errorf("无效的JSON action: %v", err)
+		}
+		return action, nil
+	}
+
+	open := strings.Index(line, "(")
+	if open == -1 || !strings.HasSuffix(line, ")") {
+		return nil, fmt.Errorf("无法识别的输入，既不是JSON action也不是funcName(args)形式")
+	}
+
+	funcName := strings.TrimSpace(line[:open])
+	argsPart := line[open+1 : len(line)-1]
+
+	var args []interface{}
+	if strings.TrimSpace(argsPart) != "" {
+		for _, rawArg := range strings.Split(argsPart, ",") {
+			args = append(args, parseREPLArg(strings.TrimSpace(rawArg)))
+		}
+	}
+
+	return map[string]interface{}{
+		"type":     "function_call",
+		"function": funcName,
+		"args":     args,
+	}, nil
+}
+
+// parseREPLArg把简写调用里的单个实参文本转换成evaluateExpression能识别的节点：
+// 带引号的当字符串、true/false当布尔、能解析成数字的当数字，其余一律当变量引用。
+func parseREPLArg(raw string) map[string]interface{} {
+	if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+		return map[string]interface{}{"type": "String", "value": raw[1 : len(raw)-1]}
+	}
+	if raw == "true" || raw == "false" {
+		return map[string]interface{}{"type": "Boolean", "value": raw == "true"}
+	}
+	if num, err := strconv.ParseFloat(raw, 64); err == nil {
+		return map[string]interface{}{"type": "Number", "value": num}
+	}
+	return map[string]interface{}{"type": "variable", "name": raw}
+}
+
+func historyFilePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".jsonlang_history"
+	}
+	return filepath.Join(home, ".jsonlang_history")
+}
+
+func appendHistory(path, line string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// printCompletions列出所有以prefix开头的函数名或当前作用域里已绑定的变量名。
+func printCompletions(prefix string, backend Backend, scope *Scope) {
+	var candidates []string
+	for name := range backend.GetFunctions() {
+		if strings.HasPrefix(name, prefix) {
+			candidates = append(candidates, name)
+		}
+	}
+	for _, frame := range scope.frames {
+		for name := range frame {
+			if strings.HasPrefix(name, prefix) {
+				candidates = append(candidates, name)
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+	if len(candidates) == 0 {
+		fmt.Println("(无匹配项)")
+		return
+	}
+	fmt.Println(strings.Join(candidates, "  "))
+}
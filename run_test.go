@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRunJSONProgramReachesStdlibFunctions是一个run级别的冒烟测试：把一个
+// 真实的.json程序写到磁盘，走main里"run"命令实际使用的runJSONProgram入口
+// 执行它。只在Go层面直接调用GoBackend的方法测不出stdlib从未被注册这种bug——
+// 之前registerFunctions误判stdlibData非nil就跳过registerDefaultFunctions，
+// 导致println这样的内建函数在真实运行时"函数 'println' 不存在"，这个测试
+// 就是为了在CI里复现并锁住这条路径。
+func TestRunJSONProgramReachesStdlibFunctions(t *testing.T) {
+	program := `{
+		"functions": {
+			"main": {
+				"actions": [
+					{
+						"type": "function_call",
+						"function": "println",
+						"args": [
+							{"type": "String", "value": "hello world"}
+						]
+					}
+				]
+			}
+		}
+	}`
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hello.json")
+	if err := os.WriteFile(path, []byte(program), 0644); err != nil {
+		t.Fatalf("写入测试程序失败: %v", err)
+	}
+
+	if err := runJSONProgram(path, defaultBackendRegistry); err != nil {
+		t.Fatalf("runJSONProgram返回了错误，stdlib函数应该在真实运行时可用: %v", err)
+	}
+}
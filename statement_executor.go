@@ -0,0 +1,294 @@
+package main
+
+import (
+	jsonlangerrors "github.com/Llonvne/jsonlang/errors"
+)
+
+// Scope是函数调用时的词法作用域：一个帧栈，每个帧是一张变量名到值的map。
+// 查找沿栈从内到外逐帧进行，赋值落在最先找到同名变量的那一帧，找不到则
+// 在当前帧新建，这样if/loop产生的块作用域就能看到外层函数的变量。
+type Scope struct {
+	frames []map[string]interface{}
+}
+
+func NewScope() *Scope {
+	return &Scope{frames: []map[string]interface{}{make(map[string]interface{})}}
+}
+
+// Push为进入一个新的代码块（比如loop的循环体）建立一个新的帧。
+func (s *Scope) Push() {
+	s.frames = append(s.frames, make(map[string]interface{}))
+}
+
+// Pop离开当前代码块，丢弃其局部变量。
+func (s *Scope) Pop() {
+	if len(s.frames) > 1 {
+		s.frames = s.frames[:len(s.frames)-1]
+	}
+}
+
+// Declare在当前最内层的帧中声明一个变量。
+func (s *Scope) Declare(name string, value interface{}) {
+	s.frames[len(s.frames)-1][name] = value
+}
+
+// Get沿帧栈从内到外查找变量。
+func (s *Scope) Get(name string) (interface{}, bool) {
+	for i := len(s.frames) - 1; i >= 0; i-- {
+		if value, ok := s.frames[i][name]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// Set沿帧栈查找已存在的同名变量并赋值；如果不存在，则在当前帧新建。
+func (s *Scope) Set(name string, value interface{}) {
+	for i := len(s.frames) - 1; i >= 0; i-- {
+		if _, ok := s.frames[i][name]; ok {
+			s.frames[i][name] = value
+			return
+		}
+	}
+	s.Declare(name, value)
+}
+
+// returnValue是return语句的哨兵包装值，用来在executeActionList的递归调用
+// 之间向上传递"已经返回"这一事实，从而在return所在的if/loop代码块里
+// 立刻中断，而不是继续把当前代码块剩余的action跑完。
+type returnValue struct {
+	value interface{}
+}
+
+// evaluateExpression统一求值字面量、变量引用和函数调用表达式。
+// node通常是{"type": ..., ...}这样的action风格map，未被识别的节点原样返回，
+// 以保持和旧版“未知类型全部当原始数据”的行为兼容。
+func evaluateExpression(program *JSONProgram, backend Backend, scope *Scope, node interface{}) interface{} {
+	nodeMap, ok := node.(map[string]interface{})
+	if !ok {
+		return node
+	}
+
+	nodeType, ok := nodeMap["type"].(string)
+	if !ok {
+		return nodeMap
+	}
+
+	switch nodeType {
+	case "String", "imports.String":
+		if value, ok := nodeMap["value"].(string); ok {
+			return value
+		}
+		return nil
+	case "Number", "imports.Number":
+		if value, ok := nodeMap["value"].(float64); ok {
+			return value
+		}
+		return nil
+	case "Boolean", "imports.Boolean":
+		if value, ok := nodeMap["value"].(bool); ok {
+			return value
+		}
+		return nil
+	case "literal":
+		return nodeMap["value"]
+	case "variable", "variable_reference":
+		name, _ := nodeMap["name"].(string)
+		if scope != nil {
+			if value, ok := scope.Get(name); ok {
+				return value
+			}
+		}
+		return nil
+	case "function_call":
+		return executeFunctionCall(program, backend, scope, nodeMap)
+	default:
+		// 不认识的type先交给TypeRegistry尝试强制转换（Int/Time/List<T>等）。
+		// 但"type"字段也被大量普通数据字面量占用（比如{"type":"circle","radius":5}
+		// 这样的业务数据），这类值在TypeRegistry里根本没注册，Coerce会返回
+		// ErrUnknownType——这种情况下必须把nodeMap原样当数据返回，而不是把
+		// 一个*jsonlangerrors.CodedError当成字面量的值；只有类型名是已知的但
+		// 值对不上（ErrTypeCoercionFailed）才是真正需要向上传播的错误。
+		value, err := backend.CoerceType(nodeType, nodeMap["value"])
+		if err != nil {
+			if ce, ok := err.(*jsonlangerrors.CodedError); ok && ce.Coder.Code() == jsonlangerrors.ErrUnknownType.Code() {
+				return nodeMap
+			}
+			return err
+		}
+		return value
+	}
+}
+
+// executeActionList依次执行一组action，返回最后一个有值的结果；
+// 如果其中触发了return，会把*returnValue一路向上传递直到executeFunction解包。
+func executeActionList(program *JSONProgram, backend Backend, scope *Scope, actions []interface{}) interface{} {
+	var result interface{}
+	var caught error
+
+	for _, action := range actions {
+		actionMap, ok := action.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		actionType, ok := actionMap["type"].(string)
+		if !ok {
+			continue
+		}
+
+		switch actionType {
+		case "function_call":
+			result = executeFunctionCall(program, backend, scope, actionMap)
+			if err, ok := result.(error); ok {
+				caught = err
+			}
+		case "go":
+			// 在新的goroutine中异步执行一次函数调用，不等待其结果
+			go executeFunctionCall(program, backend, scope, actionMap)
+		case "try":
+			body, _ := actionMap["actions"].([]interface{})
+			tryResult, tryErr := executeTryBody(program, backend, scope, body)
+			if rv, ok := tryResult.(*returnValue); ok {
+				return rv
+			}
+			result = tryResult
+			caught = tryErr
+		case "catch":
+			if caught != nil && catchMatches(actionMap, caught) {
+				body, _ := actionMap["actions"].([]interface{})
+				catchResult := executeActionList(program, backend, scope, body)
+				if rv, ok := catchResult.(*returnValue); ok {
+					return rv
+				}
+				result = catchResult
+				caught = nil
+			}
+		case "throw":
+			code, _ := actionMap["code"].(string)
+			message, _ := actionMap["message"].(string)
+			data, _ := actionMap["data"].(map[string]interface{})
+			return NewJSONError(code, message, data)
+		case "variable_declaration":
+			name, _ := actionMap["name"].(string)
+			var value interface{}
+			if valueNode, ok := actionMap["value"]; ok {
+				value = evaluateExpression(program, backend, scope, valueNode)
+			}
+			scope.Declare(name, value)
+		case "assignment":
+			target, _ := actionMap["target"].(string)
+			value := evaluateExpression(program, backend, scope, actionMap["value"])
+			scope.Set(target, value)
+		case "if_statement":
+			condition := evaluateExpression(program, backend, scope, actionMap["condition"])
+			var branch []interface{}
+			if toBoolean(condition) {
+				branch, _ = actionMap["then"].([]interface{})
+			} else {
+				branch, _ = actionMap["else"].([]interface{})
+			}
+			scope.Push()
+			branchResult := executeActionList(program, backend, scope, branch)
+			scope.Pop()
+			if rv, ok := branchResult.(*returnValue); ok {
+				return rv
+			}
+			result = branchResult
+		case "loop":
+			loopResult := executeLoop(program, backend, scope, actionMap)
+			if rv, ok := loopResult.(*returnValue); ok {
+				return rv
+			}
+			result = loopResult
+		case "return":
+			var value interface{}
+			if valueNode, ok := actionMap["value"]; ok {
+				value = evaluateExpression(program, backend, scope, valueNode)
+			}
+			return &returnValue{value: value}
+		case "literal":
+			result = actionMap["value"]
+		}
+	}
+
+	return result
+}
+
+// executeTryBody依次执行try代码块里的action，一旦某个action的结果是一个error
+// 就立刻停止——不再执行该try块里排在它后面的语句——并把这个错误连同目前
+// 为止的结果一起返回，供调用方的catch分支按错误码匹配；return语句会继续
+// 以*returnValue的形式整体向上传播，而不会被当成错误。
+func executeTryBody(program *JSONProgram, backend Backend, scope *Scope, actions []interface{}) (interface{}, error) {
+	var result interface{}
+
+	for _, action := range actions {
+		result = executeActionList(program, backend, scope, []interface{}{action})
+		if rv, ok := result.(*returnValue); ok {
+			return rv, nil
+		}
+		if err, ok := result.(error); ok {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// catchMatches判断一个catch分支是否应该处理给定的err：如果catch action带有
+// 数字code字段，只有携带相同errors.Coder.Code()的*jsonlangerrors.CodedError
+// 才会被捕获；没有code字段的catch分支匹配任意错误，保持和旧行为兼容。
+func catchMatches(catchAction map[string]interface{}, err error) bool {
+	codeFilter, hasFilter := catchAction["code"].(float64)
+	if !hasFilter {
+		return true
+	}
+	if ce, ok := err.(*jsonlangerrors.CodedError); ok {
+		return ce.Coder.Code() == int(codeFilter)
+	}
+	return false
+}
+
+// executeLoop支持两种循环形式：while风格(condition+body)和for风格(init/condition/update)。
+func executeLoop(program *JSONProgram, backend Backend, scope *Scope, loopAction map[string]interface{}) interface{} {
+	body, _ := loopAction["body"].([]interface{})
+	conditionNode, hasCondition := loopAction["condition"]
+	initActions, hasInit := loopAction["init"].([]interface{})
+	updateActions, hasUpdate := loopAction["update"].([]interface{})
+
+	scope.Push()
+	defer scope.Pop()
+
+	if hasInit {
+		if rv, ok := executeActionList(program, backend, scope, initActions).(*returnValue); ok {
+			return rv
+		}
+	}
+
+	var result interface{}
+	for {
+		if hasCondition {
+			if !toBoolean(evaluateExpression(program, backend, scope, conditionNode)) {
+				break
+			}
+		}
+
+		bodyResult := executeActionList(program, backend, scope, body)
+		if rv, ok := bodyResult.(*returnValue); ok {
+			return rv
+		}
+		result = bodyResult
+
+		if hasUpdate {
+			if rv, ok := executeActionList(program, backend, scope, updateActions).(*returnValue); ok {
+				return rv
+			}
+		}
+
+		if !hasCondition && !hasUpdate {
+			// 既没有终止条件也没有更新步骤：避免死循环，只跑一轮body
+			break
+		}
+	}
+	return result
+}
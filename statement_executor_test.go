@@ -0,0 +1,109 @@
+package main
+
+import "testing"
+
+// 回归测试：try代码块里一条语句出错后，同一个try块里排在它后面的语句不应该
+// 继续执行，并且错误必须能被紧跟在后面的catch分支捕获到。
+func TestTryCatchStopsOnFirstError(t *testing.T) {
+	backend := NewGoBackend()
+	program := NewJSONProgram(map[string]interface{}{})
+	scope := NewScope()
+
+	secondTryStatementRan := false
+	catchRan := false
+	backend.RegisterFunction("mark_second_try_statement", func(args ...interface{}) interface{} {
+		secondTryStatementRan = true
+		return nil
+	})
+	backend.RegisterFunction("mark_catch", func(args ...interface{}) interface{} {
+		catchRan = true
+		return nil
+	})
+
+	actions := []interface{}{
+		map[string]interface{}{
+			"type": "try",
+			"actions": []interface{}{
+				map[string]interface{}{
+					"type":     "function_call",
+					"function": "divide",
+					"args": []interface{}{
+						map[string]interface{}{"type": "Number", "value": 1.0},
+						map[string]interface{}{"type": "Number", "value": 0.0},
+					},
+				},
+				map[string]interface{}{
+					"type":     "function_call",
+					"function": "mark_second_try_statement",
+					"args":     []interface{}{},
+				},
+			},
+		},
+		map[string]interface{}{
+			"type": "catch",
+			"actions": []interface{}{
+				map[string]interface{}{
+					"type":     "function_call",
+					"function": "mark_catch",
+					"args":     []interface{}{},
+				},
+			},
+		},
+	}
+
+	executeActionList(program, backend, scope, actions)
+
+	if secondTryStatementRan {
+		t.Fatal("statement after the failing call in the try body should not have run")
+	}
+	if !catchRan {
+		t.Fatal("catch block should have run after the try body failed")
+	}
+}
+
+// 回归测试：一个普通的数据字面量（"type"字段不是任何已注册的类型名，比如
+// 业务自己的"circle"）必须原样当成map传下去，而不是被evaluateExpression
+// 的默认分支误当成一次失败的类型强制转换，悄悄换成一个*jsonlangerrors.CodedError。
+func TestEvaluateExpressionPassesThroughUnknownTypeLiterals(t *testing.T) {
+	backend := NewGoBackend()
+	program := NewJSONProgram(map[string]interface{}{})
+	scope := NewScope()
+
+	shapeLiteral := map[string]interface{}{
+		"type":   "circle",
+		"radius": 5.0,
+	}
+
+	result := evaluateExpression(program, backend, scope, shapeLiteral)
+	shape, ok := result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected unknown-type literal to pass through as a map, got %T: %v", result, result)
+	}
+	if shape["radius"] != 5.0 {
+		t.Fatalf("expected radius field to survive untouched, got %v", shape["radius"])
+	}
+
+	var received interface{}
+	backend.RegisterFunction("receive_shape", func(args ...interface{}) interface{} {
+		if len(args) > 0 {
+			received = args[0]
+		}
+		return nil
+	})
+
+	executeActionList(program, backend, scope, []interface{}{
+		map[string]interface{}{
+			"type":     "function_call",
+			"function": "receive_shape",
+			"args":     []interface{}{shapeLiteral},
+		},
+	})
+
+	receivedShape, ok := received.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected function_call arg to pass through as a map, got %T: %v", received, received)
+	}
+	if receivedShape["type"] != "circle" {
+		t.Fatalf("expected shape's own type field to survive untouched, got %v", receivedShape["type"])
+	}
+}
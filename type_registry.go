@@ -0,0 +1,388 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	jsonlangerrors "github.com/Llonvne/jsonlang/errors"
+)
+
+// TypeDecoder把一个原始JSON解码值（string/float64/bool/[]interface{}/map[string]interface{}/nil）
+// 转换成某个具体Go类型的值，转换失败时返回error说明原因。
+type TypeDecoder func(raw interface{}) (interface{}, error)
+
+// TypeRegistry是GoBackend上的类型强制转换表，让参数求值不再局限于
+// evaluateExpression内建的String/Number/Boolean三种类型，调用方可以用
+// RegisterType追加Int、Time、Duration等具名类型，也可以用List<T>、Map<K,V>、
+// Struct{字段:类型,...}这几种带泛型参数的写法组合出复合类型。
+type TypeRegistry struct {
+	decoders map[string]TypeDecoder
+}
+
+func newTypeRegistry() *TypeRegistry {
+	tr := &TypeRegistry{decoders: make(map[string]TypeDecoder)}
+	tr.RegisterType("String", decodeString)
+	tr.RegisterType("Boolean", decodeBoolean)
+	tr.RegisterType("Int", decodeInt)
+	tr.RegisterType("Int32", decodeInt32)
+	tr.RegisterType("Int64", decodeInt64)
+	tr.RegisterType("Float32", decodeFloat32)
+	tr.RegisterType("Number", decodeNumber)
+	tr.RegisterType("Rune", decodeRune)
+	tr.RegisterType("Time", decodeTime)
+	tr.RegisterType("Duration", decodeDuration)
+	tr.RegisterType("Bytes", decodeBytes)
+	return tr
+}
+
+// RegisterType注册一个具名类型的解码器，同名类型会被覆盖。
+func (tr *TypeRegistry) RegisterType(name string, decoder TypeDecoder) {
+	tr.decoders[name] = decoder
+}
+
+// Coerce按类型名把raw转换成对应的Go值。typeName支持一个"nullable "前缀
+// （例如"nullable Int"），表示raw为JSON null时直接返回nil而不是报错；
+// 还支持List<T>、Map<K,V>、Struct{字段:类型,...}这几种带参数的复合类型。
+func (tr *TypeRegistry) Coerce(typeName string, raw interface{}) (interface{}, error) {
+	typeName = strings.TrimSpace(typeName)
+
+	nullable := false
+	if strings.HasPrefix(typeName, "nullable ") {
+		nullable = true
+		typeName = strings.TrimSpace(strings.TrimPrefix(typeName, "nullable "))
+	}
+
+	decoder, isRegistered := tr.decoders[typeName]
+	if !isCompositeTypeName(typeName) && !isRegistered {
+		// typeName根本不是一个注册过的类型名——调用方很可能把它当成普通数据
+		// 字面量的一个字段在用（比如业务自己的{"type":"circle",...}），这种
+		// 情况下连nullable策略都不该套用，直接报"未注册"交给调用方自行决定
+		// 是报错还是把整个值当数据透传。
+		return nil, jsonlangerrors.New(jsonlangerrors.ErrUnknownType,
+			fmt.Sprintf("未注册的类型 '%s'", typeName))
+	}
+
+	if raw == nil {
+		if nullable {
+			return nil, nil
+		}
+		return nil, jsonlangerrors.New(jsonlangerrors.ErrTypeCoercionFailed,
+			fmt.Sprintf("类型 '%s' 不接受null，除非标注为nullable", typeName))
+	}
+
+	switch {
+	case strings.HasPrefix(typeName, "List<") && strings.HasSuffix(typeName, ">"):
+		return tr.coerceList(typeName, raw)
+	case strings.HasPrefix(typeName, "Map<") && strings.HasSuffix(typeName, ">"):
+		return tr.coerceMap(typeName, raw)
+	case strings.HasPrefix(typeName, "Struct{") && strings.HasSuffix(typeName, "}"):
+		return tr.coerceStruct(typeName, raw)
+	}
+
+	value, err := decoder(raw)
+	if err != nil {
+		return nil, jsonlangerrors.New(jsonlangerrors.ErrTypeCoercionFailed,
+			fmt.Sprintf("无法把值 %v（实际类型%T）转换成 '%s': %v", raw, raw, typeName, err))
+	}
+	return value, nil
+}
+
+// isCompositeTypeName判断typeName是不是List<T>、Map<K,V>、Struct{...}这几种
+// 带参数的复合类型写法之一；Coerce用它在nil/nullable检查之前先认出复合类型。
+// 之后分发到coerceList/coerceMap/coerceStruct的switch没有调用它，而是按同样的
+// 前缀/后缀条件自行区分这三种写法。
+func isCompositeTypeName(typeName string) bool {
+	return (strings.HasPrefix(typeName, "List<") && strings.HasSuffix(typeName, ">")) ||
+		(strings.HasPrefix(typeName, "Map<") && strings.HasSuffix(typeName, ">")) ||
+		(strings.HasPrefix(typeName, "Struct{") && strings.HasSuffix(typeName, "}"))
+}
+
+// wrapElementTypeError把复合类型（List/Map/Struct）内部元素类型强制转换时
+// 产生的错误，统一收敛成ErrTypeCoercionFailed再往上传播。复合类型外层本身
+// 是已识别的类型名，所以这里不能让元素类型的ErrUnknownType原样冒泡到顶层——
+// 那样evaluateExpression的默认分支会把它误当成"外层type压根没注册"，把
+// 整个复合字面量当数据透传掉，掩盖掉元素类型名写错了这种真实的错误。
+func wrapElementTypeError(typeName string, err error) error {
+	if ce, ok := err.(*jsonlangerrors.CodedError); ok && ce.Coder.Code() == jsonlangerrors.ErrUnknownType.Code() {
+		return jsonlangerrors.New(jsonlangerrors.ErrTypeCoercionFailed,
+			fmt.Sprintf("无法把类型 '%s' 的元素转换: %v", typeName, err))
+	}
+	return err
+}
+
+func (tr *TypeRegistry) coerceList(typeName string, raw interface{}) (interface{}, error) {
+	elemType := typeName[len("List<") : len(typeName)-1]
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, jsonlangerrors.New(jsonlangerrors.ErrTypeCoercionFailed,
+			fmt.Sprintf("类型 '%s' 需要一个JSON数组，实际是%T", typeName, raw))
+	}
+
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		value, err := tr.Coerce(elemType, item)
+		if err != nil {
+			return nil, wrapElementTypeError(typeName, err)
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+func (tr *TypeRegistry) coerceMap(typeName string, raw interface{}) (interface{}, error) {
+	inner := typeName[len("Map<") : len(typeName)-1]
+	parts := strings.SplitN(inner, ",", 2)
+	if len(parts) != 2 {
+		return nil, jsonlangerrors.New(jsonlangerrors.ErrUnknownType,
+			fmt.Sprintf("类型 '%s' 缺少键/值两个类型参数", typeName))
+	}
+	// JSON对象的键总是字符串，K目前只用于文档化类型意图，键本身不做强制转换。
+	valueType := strings.TrimSpace(parts[1])
+
+	items, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, jsonlangerrors.New(jsonlangerrors.ErrTypeCoercionFailed,
+			fmt.Sprintf("类型 '%s' 需要一个JSON对象，实际是%T", typeName, raw))
+	}
+
+	result := make(map[string]interface{}, len(items))
+	for key, item := range items {
+		value, err := tr.Coerce(valueType, item)
+		if err != nil {
+			return nil, wrapElementTypeError(typeName, err)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (tr *TypeRegistry) coerceStruct(typeName string, raw interface{}) (interface{}, error) {
+	inner := typeName[len("Struct{") : len(typeName)-1]
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, jsonlangerrors.New(jsonlangerrors.ErrTypeCoercionFailed,
+			fmt.Sprintf("类型 '%s' 需要一个JSON对象，实际是%T", typeName, raw))
+	}
+
+	result := make(map[string]interface{})
+	for _, fieldSpec := range strings.Split(inner, ",") {
+		parts := strings.SplitN(strings.TrimSpace(fieldSpec), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fieldName := strings.TrimSpace(parts[0])
+		fieldType := strings.TrimSpace(parts[1])
+
+		value, err := tr.Coerce(fieldType, fields[fieldName])
+		if err != nil {
+			return nil, wrapElementTypeError(typeName, err)
+		}
+		result[fieldName] = value
+	}
+	return result, nil
+}
+
+func decodeString(raw interface{}) (interface{}, error) {
+	if s, ok := raw.(string); ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("期望字符串")
+}
+
+func decodeBoolean(raw interface{}) (interface{}, error) {
+	if b, ok := raw.(bool); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("期望布尔值")
+}
+
+func decodeInt(raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case float64:
+		return int(v), nil
+	case int:
+		return v, nil
+	case string:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("期望数字或数字字符串")
+}
+
+func decodeInt32(raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case float64:
+		return int32(v), nil
+	case int:
+		return int32(v), nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		return int32(n), nil
+	}
+	return nil, fmt.Errorf("期望数字或数字字符串")
+}
+
+func decodeInt64(raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case float64:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("期望数字或数字字符串")
+}
+
+func decodeFloat32(raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case float64:
+		return float32(v), nil
+	case string:
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return nil, err
+		}
+		return float32(f), nil
+	}
+	return nil, fmt.Errorf("期望数字或数字字符串")
+}
+
+func decodeNumber(raw interface{}) (interface{}, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("期望数字或数字字符串")
+}
+
+func decodeRune(raw interface{}) (interface{}, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("期望单字符字符串")
+	}
+	runes := []rune(s)
+	if len(runes) != 1 {
+		return nil, fmt.Errorf("期望单字符字符串，实际长度为%d", len(runes))
+	}
+	return runes[0], nil
+}
+
+func decodeTime(raw interface{}) (interface{}, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("期望RFC3339时间字符串")
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func decodeDuration(raw interface{}) (interface{}, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf(`期望Go duration字符串（如"5s"）`)
+	}
+	return time.ParseDuration(s)
+}
+
+func decodeBytes(raw interface{}) (interface{}, error) {
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("期望字符串（按UTF-8编码转换成字节）")
+	}
+	return []byte(s), nil
+}
+
+// CoerceType实现Backend接口，把强制转换请求转发给gb.types。
+func (gb *GoBackend) CoerceType(typeName string, raw interface{}) (interface{}, error) {
+	return gb.types.Coerce(typeName, raw)
+}
+
+// Register用反射包裹一个普通Go函数fn，让它每个形参的Go类型自动驱动参数的强制
+// 转换（见goTypeToTypeName），然后以func(args ...interface{}) interface{}的
+// 形式通过RegisterFunction注册成JSONLang可调用的函数。
+func (gb *GoBackend) Register(name string, fn interface{}) {
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+	if fnType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("Register: '%s' 对应的值不是一个函数", name))
+	}
+
+	paramTypeNames := make([]string, fnType.NumIn())
+	for i := 0; i < fnType.NumIn(); i++ {
+		paramTypeNames[i] = goTypeToTypeName(fnType.In(i))
+	}
+
+	gb.RegisterFunction(name, func(args ...interface{}) interface{} {
+		in := make([]reflect.Value, fnType.NumIn())
+		for i := 0; i < fnType.NumIn(); i++ {
+			var raw interface{}
+			if i < len(args) {
+				raw = args[i]
+			}
+
+			coerced, err := gb.types.Coerce(paramTypeNames[i], raw)
+			if err != nil {
+				return err
+			}
+			if coerced == nil {
+				in[i] = reflect.Zero(fnType.In(i))
+			} else {
+				in[i] = reflect.ValueOf(coerced)
+			}
+		}
+
+		out := fnValue.Call(in)
+		if len(out) == 0 {
+			return nil
+		}
+		return out[0].Interface()
+	})
+}
+
+// goTypeToTypeName把一个Go反射形参类型映射到TypeRegistry里的类型名，驱动
+// Register的自动参数强制转换；映射不到已知类型时退回到"Number"，和解释器
+// 里字面量默认用float64表示数字的习惯保持一致。
+func goTypeToTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int:
+		return "Int"
+	case reflect.Int32:
+		return "Int32"
+	case reflect.Int64:
+		return "Int64"
+	case reflect.Float32:
+		return "Float32"
+	case reflect.Float64:
+		return "Number"
+	case reflect.String:
+		return "String"
+	case reflect.Bool:
+		return "Boolean"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "Bytes"
+		}
+		return "List<" + goTypeToTypeName(t.Elem()) + ">"
+	default:
+		return "Number"
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+
+	jsonlangerrors "github.com/Llonvne/jsonlang/errors"
+)
+
+// TestRegisterCoercesInt32Params锁住一个曾经崩溃的bug：goTypeToTypeName把
+// reflect.Int32和reflect.Int都映射到"Int"，而"Int"对应的解码器总是返回
+// 原生的int，导致反射调用fnValue.Call时因为实参类型是int而不是int32直接
+// panic。int32形参现在应该有自己的Int32类型名和解码器。
+func TestRegisterCoercesInt32Params(t *testing.T) {
+	backend := NewGoBackend()
+
+	backend.Register("addInt32", func(a, b int32) int32 {
+		return a + b
+	})
+
+	result := backend.ExecuteFunction("addInt32", 1.0, 2.0)
+	sum, ok := result.(int32)
+	if !ok {
+		t.Fatalf("期望结果是int32，实际是%T: %v", result, result)
+	}
+	if sum != 3 {
+		t.Fatalf("期望1+2=3，实际得到%d", sum)
+	}
+}
+
+// TestRegisterCoercesMixedIntWidths同时覆盖Int(int)和Int64这两个宽度，
+// 确认Register按反射形参类型各自解析到正确的TypeRegistry类型，互不干扰。
+func TestRegisterCoercesMixedIntWidths(t *testing.T) {
+	backend := NewGoBackend()
+
+	backend.Register("mixedWidths", func(a int, b int64) int64 {
+		return int64(a) + b
+	})
+
+	result := backend.ExecuteFunction("mixedWidths", 4.0, 5.0)
+	sum, ok := result.(int64)
+	if !ok {
+		t.Fatalf("期望结果是int64，实际是%T: %v", result, result)
+	}
+	if sum != 9 {
+		t.Fatalf("期望4+5=9，实际得到%d", sum)
+	}
+}
+
+// TestCoerceListRejectsUnregisteredElementType确保List<T>里T本身没注册时，
+// 错误会以ErrTypeCoercionFailed的形式冒出来，而不是以ErrUnknownType冒出来——
+// 后者会被evaluateExpression的默认分支误判成"外层type压根没注册"，把整个
+// 复合字面量当数据透传掉，掩盖真正写错的元素类型名。
+func TestCoerceListRejectsUnregisteredElementType(t *testing.T) {
+	registry := newTypeRegistry()
+
+	_, err := registry.Coerce("List<Circle>", []interface{}{1.0, 2.0})
+	if err == nil {
+		t.Fatal("期望元素类型'Circle'未注册时返回错误")
+	}
+
+	ce, ok := err.(*jsonlangerrors.CodedError)
+	if !ok {
+		t.Fatalf("期望返回*jsonlangerrors.CodedError，实际是%T", err)
+	}
+	if ce.Coder.Code() != jsonlangerrors.ErrTypeCoercionFailed.Code() {
+		t.Fatalf("期望错误码是ErrTypeCoercionFailed(%d)，实际是%d",
+			jsonlangerrors.ErrTypeCoercionFailed.Code(), ce.Coder.Code())
+	}
+}